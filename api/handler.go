@@ -1,13 +1,16 @@
 package api
 
 import (
+	"context"
 	"db_explorer/dbexplorer"
 	"db_explorer/pkg/router"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"net/url"
 	"strconv"
+	"strings"
 )
 
 type ExplorerHandler struct {
@@ -22,11 +25,22 @@ func NewExplorerHandler(dbexp dbexplorer.SqlExplorer) *ExplorerHandler {
 
 func (h *ExplorerHandler) RegisterRoutes(router *router.MuxRouter) {
 	router.Route("GET", "/", h.GetTables)
+	router.Route("GET", "/_schema", h.GetSchema)
+	router.Route("POST", "/_schema/reload", h.ReloadSchema)
+	router.Route("POST", "/_batch", h.Batch)
+	router.Route("GET", "/{table}/_schema", h.GetTableSchema)
+	router.Route("GET", "/{table}/_aggregate", h.Aggregate)
 	router.Route("GET", "/{table}/", h.GetRecords)
 	router.Route("GET", "/{table}/{id}/", h.GetRecord)
 	router.Route("PUT", "/{table}/", h.CreateRecord)
+	router.Route("POST", "/{table}/_bulk", h.CreateRecords)
 	router.Route("POST", "/{table}/{id}/", h.UpdateRecord)
+	router.Route("PUT", "/{table}/{id}/", h.ReplaceRecord)
+	router.Route("POST", "/{table}/_upsert", h.UpsertRecord)
 	router.Route("DELETE", "/{table}/{id}/", h.DeleteRecord)
+	router.Route("PATCH", "/{table}/", h.UpdateRecords)
+	router.Route("DELETE", "/{table}/", h.DeleteRecords)
+	router.Route("GET", "/{table}/{id}/{related}/", h.GetRelatedRecords)
 }
 
 type TablesResponse struct {
@@ -47,11 +61,103 @@ func (h *ExplorerHandler) GetTables(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// POST /_schema/reload
+func (h *ExplorerHandler) ReloadSchema(w http.ResponseWriter, r *http.Request) {
+	if err := h.explorer.ReloadSchema(r.Context()); err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	h.GetTables(w, r)
+}
+
+type SchemaResponse struct {
+	Tables []*dbexplorer.TableSchema `json:"tables"`
+}
+
+// GET /_schema
+func (h *ExplorerHandler) GetSchema(w http.ResponseWriter, r *http.Request) {
+	tables, err := h.explorer.GetSchema()
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	response := map[string]*SchemaResponse{"response": {Tables: tables}}
+	json.NewEncoder(w).Encode(response)
+}
+
+type TableSchemaResponse struct {
+	Table *dbexplorer.TableSchema `json:"table"`
+}
+
+// GET /$table/_schema
+func (h *ExplorerHandler) GetTableSchema(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+
+	ts, err := h.explorer.GetTableSchema(table)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	response := map[string]*TableSchemaResponse{"response": {Table: ts}}
+	json.NewEncoder(w).Encode(response)
+}
+
+type BatchRequest struct {
+	Operations []*dbexplorer.Operation `json:"operations"`
+}
+
+type BatchResponse struct {
+	Results []*dbexplorer.OperationResult `json:"results"`
+}
+
+// POST /_batch body={"operations": [{"op": "create", "table": "users", "data": {...}, "ref": "user"}, ...]}
+func (h *ExplorerHandler) Batch(w http.ResponseWriter, r *http.Request) {
+	var batch BatchRequest
+	if err := h.decodeBody(r, &batch); err != nil {
+		h.errorResponse(w, "body must have operations list", http.StatusBadRequest)
+		return
+	}
+
+	results, err := h.explorer.Batch(r.Context(), batch.Operations)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	response := map[string]*BatchResponse{"response": {Results: results}}
+	json.NewEncoder(w).Encode(response)
+}
+
 type RecordsResponse struct {
-	Records []map[string]interface{} `json:"records"`
+	Records    []map[string]interface{} `json:"records"`
+	NextCursor string                   `json:"next_cursor,omitempty"`
+	Total      *int                     `json:"total,omitempty"`
+	Limit      *int                     `json:"limit,omitempty"`
+	Offset     *int                     `json:"offset,omitempty"`
 }
 
-// GET /$table?limit=5&offset=7
+// query params which are not treated as column filters
+var reservedParams = map[string]bool{
+	"limit":    true,
+	"offset":   true,
+	"sort":     true,
+	"cursor":   true,
+	"fields":   true,
+	"count":    true,
+	"expand":   true,
+	"q":        true,
+	"group_by": true,
+	"metrics":  true,
+
+	"all":          true,
+	"max_affected": true,
+	"dry_run":      true,
+}
+
+// GET /$table?limit=5&offset=7&sort=-updated,title&fields=id,title&count=exact&title=foo&id__gt=10&cursor=&expand=author&q=term
 func (h *ExplorerHandler) GetRecords(w http.ResponseWriter, r *http.Request) {
 	table := router.PathValue(r, "table")
 	if !h.explorer.HasTable(table) {
@@ -59,40 +165,160 @@ func (h *ExplorerHandler) GetRecords(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	limit := 5
-	offset := 0
+	query := h.parseRecordsQuery(r)
+
+	page, err := h.explorer.GetRecords(r.Context(), table, query)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	h.recordsResponse(w, r, table, query, page)
+}
+
+// GET /$table/$id/$related?limit=5&...
+func (h *ExplorerHandler) GetRelatedRecords(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	related := router.PathValue(r, "related")
+	if !h.explorer.HasTable(table) || !h.explorer.HasTable(related) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	id, err := h.recordID(r, table)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+	query := h.parseRecordsQuery(r)
+
+	page, err := h.explorer.GetRelatedRecords(r.Context(), table, id, related, query)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	h.recordsResponse(w, r, related, query, page)
+}
+
+// recordID reads key from path like /items_users/1,2/, path id _ takes it from
+// ?pk.item_id=1&pk.user_id=2 for composite key values with commas
+func (h *ExplorerHandler) recordID(r *http.Request, table string) (dbexplorer.RecordID, error) {
+	value := router.PathValue(r, "id")
+	if value != dbexplorer.RecordIDInParams {
+		return dbexplorer.ParseRecordID(value), nil
+	}
+
+	schema, err := h.explorer.GetTableSchema(table)
+	if err != nil {
+		return nil, err
+	}
+
+	return dbexplorer.RecordIDFromParams(schema.RecordKey, r.URL.Query())
+}
+
+func (h *ExplorerHandler) parseRecordsQuery(r *http.Request) *dbexplorer.RecordsQuery {
+	query := &dbexplorer.RecordsQuery{
+		Limit:  5,
+		Offset: 0,
+	}
 
 	vals := r.URL.Query()
 	if vals.Has("limit") {
 		lim, err := strconv.Atoi(vals.Get("limit"))
 		if err == nil {
-			limit = lim
+			query.Limit = lim
 		}
 	}
 	if vals.Has("offset") {
 		offs, err := strconv.Atoi(vals.Get("offset"))
 		if err == nil {
-			offset = offs
+			query.Offset = offs
 		}
 	}
+	query.Filters = h.parseFilters(vals)
+	query.Sort = dbexplorer.ParseSort(vals.Get("sort"))
+	query.WithCursor = vals.Has("cursor")
+	query.Cursor = vals.Get("cursor")
+	query.Fields = dbexplorer.ParseFields(vals.Get("fields"))
+	query.Count = dbexplorer.CountMode(vals.Get("count"))
+	query.Search = vals.Get("q")
+
+	return query
+}
 
-	recs, err := h.explorer.GetRecords(table, offset, limit)
-	if err != nil {
-		fmt.Println(err)
-		h.errorResponse(w, "server error", http.StatusInternalServerError)
+func (h *ExplorerHandler) recordsResponse(w http.ResponseWriter, r *http.Request, table string, query *dbexplorer.RecordsQuery, page *dbexplorer.RecordsPage) {
+	expand := dbexplorer.ParseExpand(r.URL.Query().Get("expand"))
+	if err := h.explorer.ExpandRecords(r.Context(), table, page.Records, expand); err != nil {
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	rr := &RecordsResponse{Records: recs}
+	h.setPageLinks(w, r, query, page)
+
+	rr := &RecordsResponse{Records: page.Records, NextCursor: page.NextCursor}
+	if page.Total != nil {
+		rr.Total = page.Total
+		rr.Limit = &query.Limit
+		rr.Offset = &query.Offset
+	}
+
 	response := map[string]*RecordsResponse{"response": rr}
 	json.NewEncoder(w).Encode(response)
 }
 
+func (h *ExplorerHandler) parseFilters(vals url.Values) []*dbexplorer.Filter {
+	filters := []*dbexplorer.Filter{}
+	for key, values := range vals {
+		if reservedParams[key] || strings.HasPrefix(key, dbexplorer.RecordKeyParamPrefix) {
+			continue
+		}
+
+		for _, v := range values {
+			filters = append(filters, dbexplorer.ParseFilter(key, v))
+		}
+	}
+
+	return filters
+}
+
+type AggregateResponse struct {
+	Groups []map[string]interface{} `json:"groups"`
+}
+
+// GET /$table/_aggregate?group_by=status&metrics=count,sum:amount,avg:price&limit=10&title=foo
+func (h *ExplorerHandler) Aggregate(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	vals := r.URL.Query()
+	query := &dbexplorer.AggregateQuery{
+		GroupBy: dbexplorer.ParseFields(vals.Get("group_by")),
+		Metrics: dbexplorer.ParseMetrics(vals.Get("metrics")),
+		Filters: h.parseFilters(vals),
+	}
+	if lim, err := strconv.Atoi(vals.Get("limit")); err == nil {
+		query.Limit = lim
+	}
+
+	groups, err := h.explorer.Aggregate(r.Context(), table, query)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	response := map[string]*AggregateResponse{"response": {Groups: groups}}
+	json.NewEncoder(w).Encode(response)
+}
+
 type RecordResponse struct {
 	Record map[string]interface{} `json:"record"`
 }
 
-// GET /$table/$id
+// GET /$table/$id1,$id2?fields=id,title&expand=author
 func (h *ExplorerHandler) GetRecord(w http.ResponseWriter, r *http.Request) {
 	table := router.PathValue(r, "table")
 	if !h.explorer.HasTable(table) {
@@ -100,20 +326,23 @@ func (h *ExplorerHandler) GetRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(router.PathValue(r, "id"))
+	id, err := h.recordID(r, table)
 	if err != nil {
-		h.errorResponse(w, "invalid param: id. expect number", http.StatusBadRequest)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	record, err := h.explorer.GetRecord(table, id)
+	fields := dbexplorer.ParseFields(r.URL.Query().Get("fields"))
+
+	record, err := h.explorer.GetRecord(r.Context(), table, id, fields)
 	if err != nil {
-		if errors.Is(err, dbexplorer.ErrRecordNotFound) {
-			h.errorResponse(w, err.Error(), http.StatusNotFound)
-		} else {
-			fmt.Println(err)
-			h.errorResponse(w, "server error", http.StatusInternalServerError)
-		}
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	expand := dbexplorer.ParseExpand(r.URL.Query().Get("expand"))
+	if err := h.explorer.ExpandRecords(r.Context(), table, []map[string]interface{}{record}, expand); err != nil {
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
@@ -122,11 +351,12 @@ func (h *ExplorerHandler) GetRecord(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// Id is a single value or a list of values for composite primary key
 type CreateRecordResponse struct {
-	Id int `json:"id"`
+	Id interface{} `json:"id"`
 }
 
-// PUT /$table body=formdata
+// PUT /$table body=formdata or list of them
 func (h *ExplorerHandler) CreateRecord(w http.ResponseWriter, r *http.Request) {
 	table := router.PathValue(r, "table")
 	if !h.explorer.HasTable(table) {
@@ -134,32 +364,98 @@ func (h *ExplorerHandler) CreateRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	var body map[string]interface{}
-	json.NewDecoder(r.Body).Decode(&body)
+	var raw interface{}
+	h.decodeBody(r, &raw)
+
+	if list, isList := raw.([]interface{}); isList {
+		h.createRecords(w, r, table, list)
+		return
+	}
+
+	body, _ := raw.(map[string]interface{})
 
 	err := h.explorer.ValidateCreateData(table, body)
 	if err != nil {
-		h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	id, err := h.explorer.CreateRecord(table, body)
+	id, err := h.explorer.CreateRecord(r.Context(), table, body)
 	if err != nil {
-		fmt.Println(err)
-		h.errorResponse(w, "server error", http.StatusNotFound)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
 	createResponse := CreateRecordResponse{Id: id}
+	if len(id) == 1 {
+		createResponse.Id = id[0]
+	}
 	response := map[string]*CreateRecordResponse{"response": &createResponse}
 	json.NewEncoder(w).Encode(response)
 }
 
+// Ids follow request elements, failed elements have null
+type BulkCreateResponse struct {
+	Ids    []interface{}          `json:"ids"`
+	Errors []*dbexplorer.RowError `json:"errors"`
+}
+
+// POST /$table/_bulk?mode=best_effort body=[formdata, ...]
+func (h *ExplorerHandler) CreateRecords(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	var list []interface{}
+	if err := h.decodeBody(r, &list); err != nil {
+		h.errorResponse(w, "body must be a list of records", http.StatusBadRequest)
+		return
+	}
+
+	h.createRecords(w, r, table, list)
+}
+
+func (h *ExplorerHandler) createRecords(w http.ResponseWriter, r *http.Request, table string, list []interface{}) {
+	data := make([]map[string]interface{}, 0, len(list))
+	for _, item := range list {
+		rec, isRecord := item.(map[string]interface{})
+		if !isRecord {
+			h.errorResponse(w, "body must be a list of records", http.StatusBadRequest)
+			return
+		}
+		data = append(data, rec)
+	}
+
+	mode := dbexplorer.BulkMode(r.URL.Query().Get("mode"))
+	if mode == "" {
+		mode = dbexplorer.BulkAtomic
+	}
+
+	result, err := h.explorer.CreateRecords(r.Context(), table, data, mode)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	bulkResponse := BulkCreateResponse{Ids: make([]interface{}, 0, len(result.Ids)), Errors: result.Errors}
+	for _, id := range result.Ids {
+		if len(id) == 1 {
+			bulkResponse.Ids = append(bulkResponse.Ids, id[0])
+		} else {
+			bulkResponse.Ids = append(bulkResponse.Ids, id)
+		}
+	}
+	response := map[string]*BulkCreateResponse{"response": &bulkResponse}
+	json.NewEncoder(w).Encode(response)
+}
+
 type UpdateReponse struct {
 	Updated int `json:"updated"`
 }
 
-// POST /$table/$id
+// POST /$table/$id1,$id2
 func (h *ExplorerHandler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
 	table := router.PathValue(r, "table")
 	if !h.explorer.HasTable(table) {
@@ -167,29 +463,23 @@ func (h *ExplorerHandler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(router.PathValue(r, "id"))
+	id, err := h.recordID(r, table)
 	if err != nil {
-		h.errorResponse(w, "invalid param: id. expect number", http.StatusBadRequest)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	var body map[string]interface{}
-	json.NewDecoder(r.Body).Decode(&body)
+	body := h.decodeRecord(r)
 
 	err = h.explorer.ValidateUpdateData(table, body)
 	if err != nil {
-		h.errorResponse(w, err.Error(), http.StatusBadRequest)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	updated, err := h.explorer.UpdateRecord(table, id, body)
+	updated, err := h.explorer.UpdateRecord(r.Context(), table, id, body)
 	if err != nil {
-		if errors.Is(err, dbexplorer.ErrRecordNotFound) {
-			h.errorResponse(w, err.Error(), http.StatusNotFound)
-		} else {
-			fmt.Println(err)
-			h.errorResponse(w, "server error", http.StatusInternalServerError)
-		}
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
@@ -198,11 +488,71 @@ func (h *ExplorerHandler) UpdateRecord(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+type ReplaceResponse struct {
+	Created bool `json:"created"`
+}
+
+// PUT /$table/$id1,$id2 body=formdata
+func (h *ExplorerHandler) ReplaceRecord(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	id, err := h.recordID(r, table)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	body := h.decodeRecord(r)
+
+	created, err := h.explorer.ReplaceRecord(r.Context(), table, id, body)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	replaceResponse := ReplaceResponse{Created: created}
+	response := map[string]*ReplaceResponse{"response": &replaceResponse}
+	json.NewEncoder(w).Encode(response)
+}
+
+type UpsertResponse struct {
+	Id      interface{} `json:"id"`
+	Created bool        `json:"created"`
+}
+
+// POST /$table/_upsert?key=unique_index body=formdata
+func (h *ExplorerHandler) UpsertRecord(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	body := h.decodeRecord(r)
+
+	id, created, err := h.explorer.UpsertRecord(r.Context(), table, r.URL.Query().Get("key"), body)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	upsertResponse := UpsertResponse{Id: id, Created: created}
+	if len(id) == 1 {
+		upsertResponse.Id = id[0]
+	}
+	response := map[string]*UpsertResponse{"response": &upsertResponse}
+	json.NewEncoder(w).Encode(response)
+}
+
 type DeleteReponse struct {
 	Deleted int `json:"deleted"`
 }
 
-// DELETE /$table/$id
+// DELETE /$table/$id1,$id2
 func (h *ExplorerHandler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
 	table := router.PathValue(r, "table")
 	if !h.explorer.HasTable(table) {
@@ -210,20 +560,15 @@ func (h *ExplorerHandler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	id, err := strconv.Atoi(router.PathValue(r, "id"))
+	id, err := h.recordID(r, table)
 	if err != nil {
-		h.errorResponse(w, "invalid param: id. expect number", http.StatusBadRequest)
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
-	deleted, err := h.explorer.DeleteRecord(table, id)
+	deleted, err := h.explorer.DeleteRecord(r.Context(), table, id)
 	if err != nil {
-		if errors.Is(err, dbexplorer.ErrRecordNotFound) {
-			h.errorResponse(w, err.Error(), http.StatusNotFound)
-		} else {
-			fmt.Println(err)
-			h.errorResponse(w, "server error", http.StatusInternalServerError)
-		}
+		h.explorerErrorResponse(w, err)
 		return
 	}
 
@@ -232,11 +577,129 @@ func (h *ExplorerHandler) DeleteRecord(w http.ResponseWriter, r *http.Request) {
 	json.NewEncoder(w).Encode(response)
 }
 
+// Updated and Deleted are omitted in dry run
+type WriteResponse struct {
+	Matched int  `json:"matched"`
+	Updated *int `json:"updated,omitempty"`
+	Deleted *int `json:"deleted,omitempty"`
+}
+
+// PATCH /$table?status=new&max_affected=100&dry_run=true body=formdata
+func (h *ExplorerHandler) UpdateRecords(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	query := h.parseWhereQuery(r)
+	body := h.decodeRecord(r)
+
+	result, err := h.explorer.UpdateRecords(r.Context(), table, query, body)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	writeResponse := WriteResponse{Matched: result.Matched}
+	if !query.DryRun {
+		writeResponse.Updated = &result.Affected
+	}
+	response := map[string]*WriteResponse{"response": &writeResponse}
+	json.NewEncoder(w).Encode(response)
+}
+
+// DELETE /$table?status=old&all=true&max_affected=100&dry_run=true
+func (h *ExplorerHandler) DeleteRecords(w http.ResponseWriter, r *http.Request) {
+	table := router.PathValue(r, "table")
+	if !h.explorer.HasTable(table) {
+		h.errorResponse(w, "unknown table", http.StatusNotFound)
+		return
+	}
+
+	query := h.parseWhereQuery(r)
+
+	result, err := h.explorer.DeleteRecords(r.Context(), table, query)
+	if err != nil {
+		h.explorerErrorResponse(w, err)
+		return
+	}
+
+	writeResponse := WriteResponse{Matched: result.Matched}
+	if !query.DryRun {
+		writeResponse.Deleted = &result.Affected
+	}
+	response := map[string]*WriteResponse{"response": &writeResponse}
+	json.NewEncoder(w).Encode(response)
+}
+
+func (h *ExplorerHandler) parseWhereQuery(r *http.Request) *dbexplorer.WhereQuery {
+	vals := r.URL.Query()
+
+	query := &dbexplorer.WhereQuery{Filters: h.parseFilters(vals)}
+	query.All, _ = strconv.ParseBool(vals.Get("all"))
+	query.DryRun, _ = strconv.ParseBool(vals.Get("dry_run"))
+	if max, err := strconv.Atoi(vals.Get("max_affected")); err == nil {
+		query.MaxAffected = max
+	}
+
+	return query
+}
+
+// decodeRecord keeps numbers as json.Number, so big ints and decimals are not rounded
+func (h *ExplorerHandler) decodeRecord(r *http.Request) map[string]interface{} {
+	var body map[string]interface{}
+	h.decodeBody(r, &body)
+
+	return body
+}
+
+func (h *ExplorerHandler) decodeBody(r *http.Request, body interface{}) error {
+	decoder := json.NewDecoder(r.Body)
+	decoder.UseNumber()
+
+	return decoder.Decode(body)
+}
+
 type ErrorResponse struct {
-	Error string `json:"error"`
+	Error  string                   `json:"error"`
+	Errors []*dbexplorer.FieldError `json:"errors,omitempty"`
+	Rows   []*dbexplorer.RowError   `json:"rows,omitempty"`
 }
 
 func (h *ExplorerHandler) errorResponse(w http.ResponseWriter, errorMsg string, code int) {
 	w.WriteHeader(code)
 	json.NewEncoder(w).Encode(ErrorResponse{Error: errorMsg})
 }
+
+func (h *ExplorerHandler) explorerErrorResponse(w http.ResponseWriter, err error) {
+	var verr *dbexplorer.ValidationError
+	var berr *dbexplorer.BulkError
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		// client has gone, nobody reads the response
+		return
+	case dbexplorer.IsTimeout(err):
+		h.errorResponse(w, "query timeout", http.StatusGatewayTimeout)
+	case errors.As(err, &verr):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: err.Error(), Errors: verr.Errors})
+	case errors.As(err, &berr):
+		w.WriteHeader(http.StatusUnprocessableEntity)
+		json.NewEncoder(w).Encode(ErrorResponse{Error: berr.Error(), Rows: berr.Rows})
+	case errors.Is(err, dbexplorer.ErrTableNotFound), errors.Is(err, dbexplorer.ErrRecordNotFound),
+		errors.Is(err, dbexplorer.ErrRelationNotFound):
+		h.errorResponse(w, err.Error(), http.StatusNotFound)
+	case errors.Is(err, dbexplorer.ErrInvalidQuery), errors.Is(err, dbexplorer.ErrInvalidID):
+		h.errorResponse(w, err.Error(), http.StatusBadRequest)
+	case errors.Is(err, dbexplorer.ErrReadOnlyTable):
+		h.errorResponse(w, err.Error(), http.StatusMethodNotAllowed)
+	case errors.Is(err, dbexplorer.ErrTooManyRecords), errors.Is(err, dbexplorer.ErrDuplicateRecord),
+		errors.Is(err, dbexplorer.ErrMissingReference):
+		h.errorResponse(w, err.Error(), http.StatusConflict)
+	default:
+		fmt.Println(err)
+		h.errorResponse(w, "server error", http.StatusInternalServerError)
+	}
+}
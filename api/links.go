@@ -0,0 +1,66 @@
+package api
+
+import (
+	"db_explorer/dbexplorer"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// setPageLinks writes RFC 8288 Link header with first/prev/next/last pages
+func (h *ExplorerHandler) setPageLinks(w http.ResponseWriter, r *http.Request, query *dbexplorer.RecordsQuery, page *dbexplorer.RecordsPage) {
+	if query.Limit <= 0 {
+		return
+	}
+
+	links := []string{}
+
+	if query.WithCursor {
+		if page.NextCursor != "" {
+			links = append(links, pageLink(r, "next", map[string]string{"cursor": page.NextCursor}))
+		}
+		// last page has no links, empty header is not sent
+		if len(links) > 0 {
+			w.Header().Set("Link", strings.Join(links, ", "))
+		}
+		return
+	}
+
+	links = append(links, pageLink(r, "first", map[string]string{"offset": "0"}))
+
+	if query.Offset > 0 {
+		prev := query.Offset - query.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		links = append(links, pageLink(r, "prev", map[string]string{"offset": strconv.Itoa(prev)}))
+	}
+
+	next := query.Offset + query.Limit
+	hasNext := len(page.Records) == query.Limit
+	if page.Total != nil {
+		hasNext = next < *page.Total
+	}
+	if hasNext {
+		links = append(links, pageLink(r, "next", map[string]string{"offset": strconv.Itoa(next)}))
+	}
+
+	if page.Total != nil && *page.Total > 0 {
+		last := (*page.Total - 1) / query.Limit * query.Limit
+		links = append(links, pageLink(r, "last", map[string]string{"offset": strconv.Itoa(last)}))
+	}
+
+	w.Header().Set("Link", strings.Join(links, ", "))
+}
+
+func pageLink(r *http.Request, rel string, params map[string]string) string {
+	vals := r.URL.Query()
+	for k, v := range params {
+		vals.Set(k, v)
+	}
+
+	u := url.URL{Path: r.URL.Path, RawQuery: vals.Encode()}
+
+	return "<" + u.String() + `>; rel="` + rel + `"`
+}
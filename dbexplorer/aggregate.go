@@ -0,0 +1,227 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type AggregateFunc string
+
+const (
+	AggCount AggregateFunc = "count"
+	AggSum   AggregateFunc = "sum"
+	AggAvg   AggregateFunc = "avg"
+	AggMin   AggregateFunc = "min"
+	AggMax   AggregateFunc = "max"
+)
+
+const metricFieldSeparator = ":"
+
+var aggregateFuncsSql = map[AggregateFunc]string{
+	AggCount: "COUNT",
+	AggSum:   "SUM",
+	AggAvg:   "AVG",
+	AggMin:   "MIN",
+	AggMax:   "MAX",
+}
+
+// Metric is aggregate over field, count without field counts rows
+type Metric struct {
+	Func  AggregateFunc
+	Field string
+}
+
+// Name is result key of metric like count or sum_amount
+func (m *Metric) Name() string {
+	if m.Field == "" {
+		return string(m.Func)
+	}
+
+	return string(m.Func) + "_" + m.Field
+}
+
+type AggregateQuery struct {
+	GroupBy []string
+	Metrics []*Metric
+	Filters []*Filter
+	Limit   int
+}
+
+// ParseMetrics builds metrics from query param like count,sum:amount,avg:price
+func ParseMetrics(value string) []*Metric {
+	metrics := []*Metric{}
+	for _, item := range strings.Split(value, ",") {
+		item = strings.TrimSpace(item)
+		if item == "" {
+			continue
+		}
+
+		m := &Metric{Func: AggregateFunc(item)}
+		if idx := strings.Index(item, metricFieldSeparator); idx >= 0 {
+			m.Func = AggregateFunc(item[:idx])
+			m.Field = item[idx+len(metricFieldSeparator):]
+		}
+
+		metrics = append(metrics, m)
+	}
+
+	return metrics
+}
+
+// Aggregate groups filtered rows by columns, without metrics rows are counted
+func (exp *Explorer) Aggregate(ctx context.Context, table string, query *AggregateQuery) ([]map[string]interface{}, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opRead)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return nil, ErrTableNotFound
+	}
+
+	groupFields, err := exp.selectFields(table, query.GroupBy)
+	if err != nil {
+		return nil, err
+	}
+	if len(query.GroupBy) == 0 {
+		groupFields = nil
+	}
+
+	metrics := query.Metrics
+	if len(metrics) == 0 {
+		metrics = []*Metric{{Func: AggCount}}
+	}
+
+	metricFields := make([]*TableField, len(metrics))
+	columns := make([]string, 0, len(groupFields)+len(metrics))
+	// group fields and metrics share result row, one name must not hide another
+	names := make(map[string]bool, len(groupFields)+len(metrics))
+	for _, f := range groupFields {
+		columns = append(columns, exp.quote(f.Name))
+		names[f.Name] = true
+	}
+	for i, m := range metrics {
+		if names[m.Name()] {
+			return nil, fmt.Errorf("%w: metric %s has the same name as group field or other metric", ErrInvalidQuery, m.Name())
+		}
+		names[m.Name()] = true
+
+		expr, field, err := exp.buildMetric(table, m)
+		if err != nil {
+			return nil, err
+		}
+
+		metricFields[i] = field
+		columns = append(columns, fmt.Sprintf("%s AS %s", expr, exp.quote(m.Name())))
+	}
+
+	where, args, err := exp.buildWhere(table, query.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	sqlQuery := exp.builder().selectFrom(strings.Join(columns, ", "), table, where)
+	if len(groupFields) > 0 {
+		groupBy := exp.buildColumns(groupFields)
+		sqlQuery += " GROUP BY " + groupBy + " ORDER BY " + groupBy
+	}
+	if query.Limit > 0 {
+		sqlQuery += fmt.Sprintf(" LIMIT %d", query.Limit)
+	}
+
+	rows, err := exp.bind(exp.db).QueryContext(ctx, exp.readQuery(sqlQuery), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	values := make([]sql.NullString, len(columns))
+	addrs := make([]interface{}, len(columns))
+	for i := range values {
+		addrs[i] = &values[i]
+	}
+
+	result := []map[string]interface{}{}
+	for rows.Next() {
+		if err := rows.Scan(addrs...); err != nil {
+			return nil, err
+		}
+
+		row := make(map[string]interface{}, len(columns))
+		for i, f := range groupFields {
+			row[f.Name] = nil
+			if values[i].Valid {
+				row[f.Name] = readFieldValue(f, values[i].String)
+			}
+		}
+		for i, m := range metrics {
+			row[m.Name()] = readMetricValue(m, metricFields[i], values[len(groupFields)+i])
+		}
+
+		result = append(result, row)
+	}
+
+	return result, rows.Err()
+}
+
+func (exp *Explorer) buildMetric(table string, m *Metric) (string, *TableField, error) {
+	sqlFunc, known := aggregateFuncsSql[m.Func]
+	if !known {
+		return "", nil, fmt.Errorf("%w: unknown metric %s", ErrInvalidQuery, m.Func)
+	}
+
+	if m.Field == "" {
+		if m.Func != AggCount {
+			return "", nil, fmt.Errorf("%w: %s expects field", ErrInvalidQuery, m.Func)
+		}
+		return "COUNT(*)", nil, nil
+	}
+
+	field := exp.getField(table, m.Field)
+	if field == nil {
+		return "", nil, fmt.Errorf("%w: unknown field %s", ErrInvalidQuery, m.Field)
+	}
+
+	if (m.Func == AggSum || m.Func == AggAvg) && !isNumericKind(field.Type.Kind) {
+		return "", nil, fmt.Errorf("%w: %s expects numeric field %s", ErrInvalidQuery, m.Func, field.Name)
+	}
+
+	// postgres has no min and max of boolean
+	if (m.Func == AggMin || m.Func == AggMax) && field.Type.Kind == KindBool {
+		return "", nil, fmt.Errorf("%w: %s does not accept boolean field %s", ErrInvalidQuery, m.Func, field.Name)
+	}
+
+	return fmt.Sprintf("%s(%s)", sqlFunc, exp.quote(field.Name)), field, nil
+}
+
+func isNumericKind(kind TypeKind) bool {
+	switch kind {
+	case KindInt, KindDecimal, KindFloat, KindYear:
+		return true
+	}
+
+	return false
+}
+
+// readMetricValue gives count as number, sum and avg as exact decimal, min and max typed as field
+func readMetricValue(m *Metric, field *TableField, value sql.NullString) interface{} {
+	if !value.Valid {
+		return nil
+	}
+
+	switch m.Func {
+	case AggCount:
+		if count, err := strconv.ParseInt(value.String, 10, 64); err == nil {
+			return count
+		}
+	case AggSum, AggAvg:
+		return json.Number(value.String)
+	case AggMin, AggMax:
+		return readFieldValue(field, value.String)
+	}
+
+	return value.String
+}
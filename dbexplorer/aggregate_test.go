@@ -0,0 +1,40 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"testing"
+)
+
+func TestAggregateNamesClash(t *testing.T) {
+	// table "order" has field named as count metric
+	exp := testExplorer(SQLite(), "count")
+
+	cases := []*AggregateQuery{
+		{GroupBy: []string{"count"}, Metrics: ParseMetrics("count")},
+		{GroupBy: []string{"id"}, Metrics: ParseMetrics("max:id,max:id")},
+		{GroupBy: []string{"count"}},
+	}
+
+	for _, q := range cases {
+		_, err := exp.Aggregate(context.Background(), "order", q)
+		if !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("group by %v metrics %d: expected invalid query, got %v", q.GroupBy, len(q.Metrics), err)
+		}
+	}
+}
+
+func TestAggregateBoolMinMax(t *testing.T) {
+	exp := testExplorer(Postgres(), "title")
+	exp.schema.saveField("order", &TableField{Name: "done", Type: &ColumnType{Name: "bool", Kind: KindBool}})
+
+	for _, m := range ParseMetrics("min:done,max:done") {
+		if _, _, err := exp.buildMetric("order", m); !errors.Is(err, ErrInvalidQuery) {
+			t.Errorf("%s: expected invalid query, got %v", m.Name(), err)
+		}
+	}
+
+	if _, _, err := exp.buildMetric("order", &Metric{Func: AggCount, Field: "done"}); err != nil {
+		t.Errorf("count of bool: %s", err)
+	}
+}
@@ -0,0 +1,297 @@
+package dbexplorer
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type BatchOp string
+
+const (
+	OpCreate BatchOp = "create"
+	OpUpdate BatchOp = "update"
+	OpDelete BatchOp = "delete"
+)
+
+// refKey marks value taken from earlier operation: {"$ref": "user.user_id"} is key field
+// of record created by operation with ref user, {"$ref": "user"} is its whole key.
+// Every operation is also referenced by index: {"$ref": "0.user_id"}, so own refs can not be numbers
+const refKey = "$ref"
+
+// Operation is one step of batch, ID is "1,2" like in path, list of values or reference
+type Operation struct {
+	Op    BatchOp                `json:"op"`
+	Table string                 `json:"table"`
+	ID    interface{}            `json:"id,omitempty"`
+	Data  map[string]interface{} `json:"data,omitempty"`
+	Ref   string                 `json:"ref,omitempty"`
+}
+
+type OperationResult struct {
+	Op      BatchOp     `json:"op"`
+	Table   string      `json:"table"`
+	ID      interface{} `json:"id,omitempty"`
+	Updated *int        `json:"updated,omitempty"`
+	Deleted *int        `json:"deleted,omitempty"`
+}
+
+// BatchError tells which operation failed, whole batch is rolled back
+type BatchError struct {
+	Index int
+	Err   error
+}
+
+func (be *BatchError) Error() string {
+	return fmt.Sprintf("operation %d: %s", be.Index, be.Err)
+}
+
+func (be *BatchError) Unwrap() error {
+	return be.Err
+}
+
+// batchKey is record key of finished operation for references
+type batchKey struct {
+	fields []*TableField
+	values []interface{}
+}
+
+// Batch runs operations in one transaction, any failure rolls back all of them
+func (exp *Explorer) Batch(ctx context.Context, ops []*Operation) ([]*OperationResult, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	for i, op := range ops {
+		if op == nil {
+			return nil, &BatchError{Index: i, Err: fmt.Errorf("%w: empty operation", ErrInvalidQuery)}
+		}
+		// index references are numbers, own names must not shadow them
+		if _, err := strconv.Atoi(op.Ref); err == nil {
+			return nil, &BatchError{Index: i, Err: fmt.Errorf("%w: ref %s is a number, numbers refer to operations by index", ErrInvalidQuery, op.Ref)}
+		}
+	}
+
+	tx, err := exp.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	refs := map[string]*batchKey{}
+	results := make([]*OperationResult, 0, len(ops))
+	for i, op := range ops {
+		res, key, err := exp.runOperation(ctx, exp.bind(tx), op, refs)
+		if err != nil {
+			return nil, &BatchError{Index: i, Err: err}
+		}
+
+		refs[fmt.Sprint(i)] = key
+		if op.Ref != "" {
+			refs[op.Ref] = key
+		}
+		results = append(results, res)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+func (exp *Explorer) runOperation(ctx context.Context, q querier, op *Operation, refs map[string]*batchKey) (*OperationResult, *batchKey, error) {
+	if !exp.HasTable(op.Table) {
+		return nil, nil, ErrTableNotFound
+	}
+
+	keyFields := exp.getKeyFields(op.Table)
+	if len(keyFields) == 0 {
+		return nil, nil, ErrReadOnlyTable
+	}
+
+	data, err := resolveRefs(op.Data, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	res := &OperationResult{Op: op.Op, Table: op.Table}
+
+	if op.Op == OpCreate {
+		if err := exp.ValidateCreateData(op.Table, data); err != nil {
+			return nil, nil, err
+		}
+
+		ids, err := exp.insertRecords(ctx, q, op.Table, []map[string]interface{}{data})
+		if err != nil {
+			return nil, nil, exp.constraintError(err)
+		}
+
+		res.ID = keyResult(ids[0])
+		return res, &batchKey{fields: keyFields, values: ids[0]}, nil
+	}
+
+	id, err := resolveID(op.ID, refs)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	id = joinSingleKey(keyFields, id)
+	if len(id) != len(keyFields) {
+		return nil, nil, fmt.Errorf("%w: expect %d key values", ErrInvalidID, len(keyFields))
+	}
+
+	key := &batchKey{fields: keyFields, values: make([]interface{}, 0, len(id))}
+	for i, v := range id {
+		key.values = append(key.values, idValue(keyFields[i], v))
+	}
+	res.ID = keyResult(key.values)
+
+	switch op.Op {
+	case OpUpdate:
+		if err := exp.ValidateUpdateData(op.Table, data); err != nil {
+			return nil, nil, err
+		}
+
+		updated, err := exp.updateRecord(ctx, q, op.Table, id, data)
+		if err != nil {
+			return nil, nil, err
+		}
+		res.Updated = &updated
+
+	case OpDelete:
+		deleted, err := exp.deleteRecord(ctx, q, op.Table, id)
+		if err != nil {
+			return nil, nil, err
+		}
+		res.Deleted = &deleted
+
+	default:
+		return nil, nil, fmt.Errorf("%w: unknown operation %s", ErrInvalidQuery, op.Op)
+	}
+
+	return res, key, nil
+}
+
+// idValue keeps numeric key as number, so it can be referenced in numeric fields
+func idValue(field *TableField, value string) interface{} {
+	if isNumericKind(field.Type.Kind) {
+		return json.Number(value)
+	}
+
+	return value
+}
+
+// keyResult is single value for one column key, list for composite one
+func keyResult(values []interface{}) interface{} {
+	if len(values) == 1 {
+		return values[0]
+	}
+
+	return values
+}
+
+func resolveRefs(data map[string]interface{}, refs map[string]*batchKey) (map[string]interface{}, error) {
+	resolved := make(map[string]interface{}, len(data))
+	for name, val := range data {
+		ref, isRef := refName(val)
+		if !isRef {
+			resolved[name] = val
+			continue
+		}
+
+		refVal, err := resolveRef(ref, refs)
+		if err != nil {
+			return nil, err
+		}
+		resolved[name] = refVal
+	}
+
+	return resolved, nil
+}
+
+func refName(val interface{}) (string, bool) {
+	obj, ok := val.(map[string]interface{})
+	if !ok || len(obj) != 1 {
+		return "", false
+	}
+
+	ref, ok := obj[refKey].(string)
+	return ref, ok
+}
+
+// resolveRef gives key field value by reference like user.user_id
+func resolveRef(ref string, refs map[string]*batchKey) (interface{}, error) {
+	name, fieldName := ref, ""
+	if idx := strings.LastIndex(ref, "."); idx >= 0 {
+		name, fieldName = ref[:idx], ref[idx+1:]
+	}
+
+	key, ex := refs[name]
+	if !ex {
+		return nil, fmt.Errorf("%w: unknown reference %s", ErrInvalidQuery, ref)
+	}
+
+	if fieldName == "" {
+		if len(key.values) != 1 {
+			return nil, fmt.Errorf("%w: reference %s is composite key, name the field", ErrInvalidQuery, ref)
+		}
+		return key.values[0], nil
+	}
+
+	for i, f := range key.fields {
+		if f.Name == fieldName {
+			return key.values[i], nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: reference %s is not a key field", ErrInvalidQuery, ref)
+}
+
+func resolveID(raw interface{}, refs map[string]*batchKey) (RecordID, error) {
+	if ref, isRef := refName(raw); isRef {
+		name := ref
+		if idx := strings.LastIndex(ref, "."); idx >= 0 {
+			val, err := resolveRef(ref, refs)
+			if err != nil {
+				return nil, err
+			}
+			return RecordID{fmt.Sprint(val)}, nil
+		}
+
+		key, ex := refs[name]
+		if !ex {
+			return nil, fmt.Errorf("%w: unknown reference %s", ErrInvalidQuery, ref)
+		}
+
+		id := make(RecordID, 0, len(key.values))
+		for _, v := range key.values {
+			id = append(id, fmt.Sprint(v))
+		}
+		return id, nil
+	}
+
+	switch v := raw.(type) {
+	case string:
+		return ParseRecordID(v), nil
+	case json.Number:
+		return RecordID{v.String()}, nil
+	case []interface{}:
+		id := make(RecordID, 0, len(v))
+		for _, item := range v {
+			if ref, isRef := refName(item); isRef {
+				val, err := resolveRef(ref, refs)
+				if err != nil {
+					return nil, err
+				}
+				item = val
+			}
+			id = append(id, fmt.Sprint(item))
+		}
+		return id, nil
+	}
+
+	return nil, fmt.Errorf("%w: operation needs id", ErrInvalidID)
+}
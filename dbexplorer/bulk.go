@@ -0,0 +1,352 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+type BulkMode string
+
+const (
+	// BulkAtomic creates all records or none
+	BulkAtomic BulkMode = "atomic"
+	// BulkBestEffort creates valid records and reports the rest
+	BulkBestEffort BulkMode = "best_effort"
+)
+
+const bulkBatchSize = 100
+
+// codes of records failed on insert, invalid ones have field errors instead
+const (
+	RowCodeDuplicate  = "duplicate"
+	RowCodeForeignKey = "foreign_key"
+	RowCodeNotNull    = "not_null"
+	RowCodeFailed     = "failed"
+)
+
+var rowCodeMessages = map[string]string{
+	RowCodeDuplicate:  "record duplicates unique key",
+	RowCodeForeignKey: "record refers to missing record",
+	RowCodeNotNull:    "record has null in not null field",
+	RowCodeFailed:     "record can not be created",
+}
+
+// constraintError turns constraint violation of single record write into explorer error,
+// other errors are returned as is
+func (exp *Explorer) constraintError(err error) error {
+	switch exp.dialect.constraint(err) {
+	case RowCodeDuplicate:
+		return ErrDuplicateRecord
+	case RowCodeForeignKey:
+		return ErrMissingReference
+	case RowCodeNotNull:
+		return fmt.Errorf("%w: %s", ErrInvalidQuery, rowCodeMessages[RowCodeNotNull])
+	}
+
+	return err
+}
+
+// RowError is failure of one element of bulk request, Index is its position in request
+type RowError struct {
+	Index  int           `json:"index"`
+	Code   string        `json:"code,omitempty"`
+	Error  string        `json:"error"`
+	Errors []*FieldError `json:"errors,omitempty"`
+}
+
+// BulkResult keeps key of created record at index of request element, failed ones have nil
+type BulkResult struct {
+	Ids    [][]interface{}
+	Errors []*RowError
+}
+
+// BulkError is returned by atomic bulk create when some elements are invalid
+// or rejected by database
+type BulkError struct {
+	Rows []*RowError
+}
+
+func (be *BulkError) Error() string {
+	msgs := make([]string, 0, len(be.Rows))
+	for _, re := range be.Rows {
+		msgs = append(msgs, fmt.Sprintf("record %d: %s", re.Index, re.Error))
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (exp *Explorer) CreateRecords(ctx context.Context, table string, data []map[string]interface{}, mode BulkMode) (*BulkResult, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return nil, ErrTableNotFound
+	}
+
+	if mode != BulkAtomic && mode != BulkBestEffort {
+		return nil, fmt.Errorf("%w: unknown mode %s", ErrInvalidQuery, mode)
+	}
+
+	keyFields := exp.getKeyFields(table)
+	if len(keyFields) == 0 {
+		return nil, ErrReadOnlyTable
+	}
+
+	result := &BulkResult{Ids: make([][]interface{}, len(data)), Errors: []*RowError{}}
+
+	valid := make([]int, 0, len(data))
+	for i, rec := range data {
+		err := exp.ValidateCreateData(table, rec)
+		if err == nil {
+			valid = append(valid, i)
+			continue
+		}
+
+		var verr *ValidationError
+		if !errors.As(err, &verr) {
+			return nil, err
+		}
+		result.Errors = append(result.Errors, &RowError{Index: i, Error: verr.Error(), Errors: verr.Errors})
+	}
+
+	if mode == BulkAtomic && len(result.Errors) > 0 {
+		return nil, &BulkError{Rows: result.Errors}
+	}
+
+	tx, err := exp.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := exp.bind(tx)
+	for start := 0; start < len(valid); start += bulkBatchSize {
+		end := start + bulkBatchSize
+		if end > len(valid) {
+			end = len(valid)
+		}
+		batch := valid[start:end]
+
+		if err := exp.insertBestEffort(ctx, q, table, data, batch, result); err != nil {
+			return nil, err
+		}
+
+		// failed batch is already inserted one by one, so every rejected record is reported
+		if mode == BulkAtomic && len(result.Errors) > 0 {
+			return nil, &BulkError{Rows: result.Errors}
+		}
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, err
+	}
+
+	sort.Slice(result.Errors, func(i, j int) bool {
+		return result.Errors[i].Index < result.Errors[j].Index
+	})
+
+	return result, nil
+}
+
+// insertBestEffort tries batch at once, on failure inserts its records one by one
+// so only failing records are reported. Savepoints keep transaction usable.
+// Timeout, cancel and invalid request fail whole call, they are not problems of a record
+func (exp *Explorer) insertBestEffort(ctx context.Context, q querier, table string, data []map[string]interface{}, batch []int, result *BulkResult) error {
+	if _, err := q.ExecContext(ctx, "SAVEPOINT bulk_batch"); err != nil {
+		return err
+	}
+
+	ids, err := exp.insertRecords(ctx, q, table, pickRecords(data, batch))
+	if err == nil {
+		for i, idx := range batch {
+			result.Ids[idx] = ids[i]
+		}
+		return nil
+	}
+
+	if _, err := q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_batch"); err != nil {
+		return err
+	}
+
+	for _, idx := range batch {
+		if _, err := q.ExecContext(ctx, "SAVEPOINT bulk_record"); err != nil {
+			return err
+		}
+
+		ids, err := exp.insertRecords(ctx, q, table, []map[string]interface{}{data[idx]})
+		if err != nil {
+			code := exp.dialect.constraint(err)
+			if code == "" && (ctx.Err() != nil || errors.Is(err, ErrInvalidQuery)) {
+				return err
+			}
+			if code == "" {
+				code = RowCodeFailed
+			}
+			result.Errors = append(result.Errors, &RowError{Index: idx, Code: code, Error: rowCodeMessages[code]})
+			if _, err := q.ExecContext(ctx, "ROLLBACK TO SAVEPOINT bulk_record"); err != nil {
+				return err
+			}
+			continue
+		}
+
+		result.Ids[idx] = ids[0]
+	}
+
+	return nil
+}
+
+func pickRecords(data []map[string]interface{}, indexes []int) []map[string]interface{} {
+	picked := make([]map[string]interface{}, 0, len(indexes))
+	for _, idx := range indexes {
+		picked = append(picked, data[idx])
+	}
+
+	return picked
+}
+
+// insertRecords runs one multi-row INSERT, columns missing in some record get DEFAULT.
+// Data must be validated, keys are returned in records order
+func (exp *Explorer) insertRecords(ctx context.Context, q querier, table string, data []map[string]interface{}) ([][]interface{}, error) {
+	if !exp.dialect.returning() {
+		return exp.insertEach(ctx, q, table, data)
+	}
+
+	columns := []*TableField{}
+	for _, field := range exp.getTableFields(table) {
+		if field.IsAutoIncrement {
+			continue
+		}
+
+		for _, rec := range data {
+			if _, ex := rec[field.Name]; ex {
+				columns = append(columns, field)
+				break
+			}
+		}
+	}
+
+	// postgres has no empty column list, any writable column gets DEFAULT
+	if len(columns) == 0 {
+		for _, field := range exp.getTableFields(table) {
+			if !field.IsGenerated {
+				columns = append(columns, field)
+				break
+			}
+		}
+	}
+
+	values := []interface{}{}
+	tuples := make([]string, 0, len(data))
+	for _, rec := range data {
+		placeholders := make([]string, 0, len(columns))
+		for _, field := range columns {
+			// omitted columns get their DEFAULT from server
+			val, ex := rec[field.Name]
+			if !ex {
+				placeholders = append(placeholders, "DEFAULT")
+				continue
+			}
+
+			placeholders = append(placeholders, "?")
+			values = append(values, writeFieldValue(field, val))
+		}
+		tuples = append(tuples, "("+strings.Join(placeholders, ",")+")")
+	}
+
+	keyFields := exp.getKeyFields(table)
+	query := exp.builder().insert(table, columns, tuples) + " RETURNING " + exp.buildColumns(keyFields)
+	rows, err := q.QueryContext(ctx, query, values...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	res, err := exp.scanRecords(table, rows)
+	if err != nil {
+		return nil, err
+	}
+	if len(res) != len(data) {
+		return nil, ErrRecordNotFound
+	}
+
+	ids := make([][]interface{}, 0, len(res))
+	for _, rec := range res {
+		ids = append(ids, exp.recordKey(table, rec))
+	}
+
+	return ids, nil
+}
+
+// insertEach inserts records one by one for servers without RETURNING. Key is made of
+// last insert id for auto increment field and data for others, then read back typed
+func (exp *Explorer) insertEach(ctx context.Context, q querier, table string, data []map[string]interface{}) ([][]interface{}, error) {
+	keyFields := exp.getKeyFields(table)
+
+	ids := make([][]interface{}, 0, len(data))
+	for _, rec := range data {
+		// key is read back by data, so key field left to server default can not be found after insert
+		for _, kf := range keyFields {
+			if _, ex := rec[kf.Name]; !ex && !kf.IsAutoIncrement {
+				return nil, fmt.Errorf("%w: key field %s must be passed, it is not known after insert", ErrInvalidQuery, kf.Name)
+			}
+		}
+
+		columns := []*TableField{}
+		values := []interface{}{}
+		for _, field := range exp.getTableFields(table) {
+			val, ex := rec[field.Name]
+			if !ex || field.IsAutoIncrement {
+				continue
+			}
+
+			columns = append(columns, field)
+			values = append(values, writeFieldValue(field, val))
+		}
+
+		query := exp.dialect.insertDefaults(table)
+		if len(columns) > 0 {
+			query = exp.builder().insert(table, columns, []string{"(" + placeholders(len(values)) + ")"})
+		}
+
+		res, err := q.ExecContext(ctx, query, values...)
+		if err != nil {
+			return nil, err
+		}
+
+		keyArgs := make([]interface{}, 0, len(keyFields))
+		for _, kf := range keyFields {
+			if kf.IsAutoIncrement {
+				lastID, err := res.LastInsertId()
+				if err != nil {
+					return nil, err
+				}
+				keyArgs = append(keyArgs, lastID)
+				continue
+			}
+
+			keyArgs = append(keyArgs, writeFieldValue(kf, rec[kf.Name]))
+		}
+
+		query = exp.builder().selectFrom(exp.buildColumns(keyFields), table, " WHERE "+exp.builder().equal(keyFields))
+		rows, err := q.QueryContext(ctx, query, keyArgs...)
+		if err != nil {
+			return nil, err
+		}
+		found, err := exp.scanRecords(table, rows)
+		rows.Close()
+		if err != nil {
+			return nil, err
+		}
+		if len(found) == 0 {
+			return nil, ErrRecordNotFound
+		}
+
+		ids = append(ids, exp.recordKey(table, found[0]))
+	}
+
+	return ids, nil
+}
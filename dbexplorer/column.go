@@ -2,7 +2,6 @@ package dbexplorer
 
 import (
 	"database/sql"
-	"reflect"
 	"strings"
 )
 
@@ -30,10 +29,49 @@ func (col *column) IsNullable() bool {
 	return col.Null.String == "YES"
 }
 
-func (col *column) GetType() reflect.Kind {
-	if strings.HasPrefix(col.Type, "int") {
-		return reflect.Int
+func (col *column) IsAutoIncrement() bool {
+	return strings.Contains(col.Extra.String, "auto_increment")
+}
+
+// mysql marks expression defaults as DEFAULT_GENERATED, they are not generated columns
+func (col *column) IsGenerated() bool {
+	extra := strings.ToUpper(col.Extra.String)
+	return strings.Contains(extra, "VIRTUAL GENERATED") ||
+		strings.Contains(extra, "STORED GENERATED") ||
+		strings.Contains(extra, "PERSISTENT GENERATED")
+}
+
+func (col *column) IsOnUpdate() bool {
+	return strings.Contains(strings.ToLower(col.Extra.String), "on update")
+}
+
+func (col *column) GetDefault() *string {
+	if !col.Default.Valid {
+		return nil
 	}
 
-	return reflect.String
+	def := col.Default.String
+	return &def
+}
+
+func (col *column) GetType() *ColumnType {
+	return ParseColumnType(col.Type)
+}
+
+func (col *column) GetComment() string {
+	return col.Comment.String
+}
+
+func (col *column) field() *TableField {
+	return &TableField{
+		Name:            col.GetName(),
+		Type:            col.GetType(),
+		IsNullable:      col.IsNullable(),
+		IsPrimary:       col.IsPrimary(),
+		IsAutoIncrement: col.IsAutoIncrement(),
+		IsGenerated:     col.IsGenerated(),
+		IsOnUpdate:      col.IsOnUpdate(),
+		Default:         col.GetDefault(),
+		Comment:         col.GetComment(),
+	}
 }
@@ -0,0 +1,46 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+)
+
+type CountMode string
+
+const (
+	CountNone      CountMode = ""
+	CountExact     CountMode = "exact"
+	CountEstimated CountMode = "estimated"
+)
+
+func (exp *Explorer) countRecords(ctx context.Context, table string, mode CountMode, where string, args []interface{}) (*int, error) {
+	var total int
+
+	switch mode {
+	case CountNone:
+		return nil, nil
+
+	case CountEstimated:
+		// table statistics know nothing about filters
+		if where != "" {
+			return exp.countRecords(ctx, table, CountExact, where, args)
+		}
+
+		rows, err := exp.dialect.estimateCount(ctx, exp.db, table)
+		if err != nil {
+			return nil, err
+		}
+		total = rows
+
+	case CountExact:
+		query := exp.builder().selectFrom("COUNT(*)", table, where)
+		if err := exp.bind(exp.db).QueryRowContext(ctx, exp.readQuery(query), args...).Scan(&total); err != nil {
+			return nil, err
+		}
+
+	default:
+		return nil, fmt.Errorf("%w: count expects exact or estimated", ErrInvalidQuery)
+	}
+
+	return &total, nil
+}
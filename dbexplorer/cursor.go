@@ -0,0 +1,122 @@
+package dbexplorer
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// cursor keeps order fields and their values from the last record of a page
+type cursor struct {
+	Fields []string      `json:"f"`
+	Values []interface{} `json:"v"`
+}
+
+func encodeCursor(order []*orderField, rec map[string]interface{}) (string, bool) {
+	c := cursor{
+		Fields: make([]string, 0, len(order)),
+		Values: make([]interface{}, 0, len(order)),
+	}
+
+	for _, of := range order {
+		val, ex := rec[of.Field.Name]
+		if !ex || val == nil {
+			return "", false
+		}
+
+		c.Fields = append(c.Fields, of.Field.Name)
+		c.Values = append(c.Values, val)
+	}
+
+	data, err := json.Marshal(c)
+	if err != nil {
+		return "", false
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), true
+}
+
+func decodeCursor(value string) (*cursor, error) {
+	data, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+
+	c := &cursor{}
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	if err := decoder.Decode(c); err != nil || len(c.Fields) != len(c.Values) {
+		return nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+	}
+
+	return c, nil
+}
+
+// canUseCursor reports whether order fields identify a record and never hold NULL
+func (exp *Explorer) canUseCursor(table string, order []*orderField) bool {
+	if len(exp.getKeyFields(table)) == 0 {
+		return false
+	}
+
+	for _, of := range order {
+		if of.Field.IsNullable {
+			return false
+		}
+	}
+
+	return true
+}
+
+// buildSeek makes condition to continue right after the cursor record:
+// (a > ?) OR (a = ? AND b > ?) OR ...
+func (exp *Explorer) buildSeek(table string, order []*orderField, value string) (string, []interface{}, error) {
+	if !exp.canUseCursor(table, order) {
+		return "", nil, fmt.Errorf("%w: cursor needs not null sort fields", ErrInvalidQuery)
+	}
+
+	c, err := decodeCursor(value)
+	if err != nil {
+		return "", nil, err
+	}
+
+	if len(c.Fields) != len(order) {
+		return "", nil, fmt.Errorf("%w: cursor does not match sort", ErrInvalidQuery)
+	}
+
+	values := make([]interface{}, len(order))
+	for i, of := range order {
+		if c.Fields[i] != of.Field.Name {
+			return "", nil, fmt.Errorf("%w: cursor does not match sort", ErrInvalidQuery)
+		}
+
+		val, err := parseFieldValue(of.Field, fmt.Sprint(c.Values[i]))
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: invalid cursor", ErrInvalidQuery)
+		}
+		values[i] = val
+	}
+
+	b := exp.builder()
+	args := []interface{}{}
+	alternatives := make([]string, 0, len(order))
+	for i, of := range order {
+		parts := make([]string, 0, i+1)
+		for j := 0; j < i; j++ {
+			parts = append(parts, b.cond(order[j].Field, "="))
+			args = append(args, values[j])
+		}
+
+		if of.Desc {
+			parts = append(parts, b.cond(of.Field, "<"))
+		} else {
+			parts = append(parts, b.cond(of.Field, ">"))
+		}
+		args = append(args, values[i])
+
+		alternatives = append(alternatives, "("+strings.Join(parts, " AND ")+")")
+	}
+
+	return strings.Join(alternatives, " OR "), args, nil
+}
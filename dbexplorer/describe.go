@@ -0,0 +1,96 @@
+package dbexplorer
+
+// TableSchema describes table for clients building forms
+type TableSchema struct {
+	Name        string              `json:"name"`
+	Fields      []*FieldSchema      `json:"fields"`
+	RecordKey   []string            `json:"record_key"`
+	ReadOnly    bool                `json:"read_only"`
+	Indexes     []*IndexSchema      `json:"indexes"`
+	ForeignKeys []*ForeignKeySchema `json:"foreign_keys"`
+}
+
+type FieldSchema struct {
+	*TableField
+	Required bool `json:"required"`
+}
+
+type IndexSchema struct {
+	Name       string   `json:"name"`
+	Unique     bool     `json:"unique"`
+	Type       string   `json:"type"`
+	Fields     []string `json:"fields"`
+	Expression bool     `json:"expression"`
+}
+
+type ForeignKeySchema struct {
+	Name      string   `json:"name"`
+	Fields    []string `json:"fields"`
+	RefTable  string   `json:"ref_table"`
+	RefFields []string `json:"ref_fields"`
+}
+
+func (exp *Explorer) GetSchema() ([]*TableSchema, error) {
+	s := exp.current()
+
+	tables := make([]*TableSchema, 0, len(s.tableNames))
+	for _, table := range s.tableNames {
+		tables = append(tables, s.describe(table))
+	}
+
+	return tables, nil
+}
+
+func (exp *Explorer) GetTableSchema(table string) (*TableSchema, error) {
+	s := exp.current()
+	if _, has := s.tables[table]; !has {
+		return nil, ErrTableNotFound
+	}
+
+	return s.describe(table), nil
+}
+
+func (s *schema) describe(table string) *TableSchema {
+	ts := &TableSchema{
+		Name:        table,
+		Fields:      make([]*FieldSchema, 0, len(s.tableFields[table])),
+		RecordKey:   fieldNames(s.recordKeys[table]),
+		ReadOnly:    len(s.recordKeys[table]) == 0,
+		Indexes:     make([]*IndexSchema, 0, len(s.indexes[table])),
+		ForeignKeys: make([]*ForeignKeySchema, 0, len(s.foreignKeys[table])),
+	}
+
+	for _, field := range s.tableFields[table] {
+		ts.Fields = append(ts.Fields, &FieldSchema{TableField: field, Required: isRequired(field) && !field.IsAutoIncrement})
+	}
+
+	for _, idx := range s.indexes[table] {
+		ts.Indexes = append(ts.Indexes, &IndexSchema{
+			Name:       idx.Name,
+			Unique:     idx.IsUnique,
+			Type:       idx.Type,
+			Fields:     fieldNames(idx.Fields),
+			Expression: idx.hasExpression,
+		})
+	}
+
+	for _, fk := range s.foreignKeys[table] {
+		ts.ForeignKeys = append(ts.ForeignKeys, &ForeignKeySchema{
+			Name:      fk.Name,
+			Fields:    fieldNames(fk.Fields),
+			RefTable:  fk.RefTable,
+			RefFields: fk.RefFields,
+		})
+	}
+
+	return ts
+}
+
+func fieldNames(fields []*TableField) []string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, f.Name)
+	}
+
+	return names
+}
@@ -0,0 +1,194 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Dialect is sql flavour of database server: how schema is read, how
+// placeholders and identifiers are written. Dialects are implemented in this package
+type Dialect interface {
+	Name() string
+
+	// QuoteIdent quotes table or column name
+	QuoteIdent(name string) string
+
+	// detect reads server version to pick supported features, it runs once on Init
+	detect(ctx context.Context, q querier) error
+
+	// rebind turns ? placeholders of built query into dialect ones
+	rebind(query string) string
+
+	tables(ctx context.Context, q querier) ([]string, error)
+	columns(ctx context.Context, q querier, table string) ([]*TableField, error)
+	indexes(ctx context.Context, q querier, table string) ([]*indexPart, error)
+	foreignKeys(ctx context.Context, q querier, table string) ([]*foreignKeyPart, error)
+	checksum(ctx context.Context, q querier) (string, error)
+	estimateCount(ctx context.Context, q querier, table string) (int, error)
+
+	// returning tells whether INSERT can give back key columns, otherwise
+	// records are inserted one by one and keys come from last insert id
+	returning() bool
+
+	// insertDefaults is INSERT of record with every column DEFAULT
+	insertDefaults(table string) string
+
+	// upsert adds conflict clause to insert and reports whether record was created
+	upsert(ctx context.Context, q querier, uq *upsertQuery) (created bool, err error)
+
+	// upsertAnyKey tells whether upsert updates record on conflict in any unique index,
+	// not only in Conflict fields
+	upsertAnyKey() bool
+
+	// lockedCount counts records of quoted "table WHERE ..." locking them till transaction end
+	lockedCount(from string) string
+
+	// constraint classifies insert error as RowCode of violated constraint, empty for others
+	constraint(err error) string
+
+	// statementTimeout asks server to stop query after timeout, query is unchanged when not supported
+	statementTimeout(query string, timeout time.Duration) string
+
+	// likeText makes quoted non-text column comparable with LIKE
+	likeText(column string) string
+}
+
+// indexPart is one column of index, parts of the same index go one by one
+type indexPart struct {
+	Index   string
+	Unique  bool
+	Primary bool
+	Type    string
+	// Column is empty for expression and partial index parts
+	Column string
+}
+
+type foreignKeyPart struct {
+	Name      string
+	Column    string
+	RefTable  string
+	RefColumn string
+}
+
+// upsertQuery is INSERT ... VALUES of record, when record with the same Conflict
+// fields exists its Sets are changed instead. ConflictArgs are values of Conflict fields
+type upsertQuery struct {
+	Table        string
+	Insert       string
+	Args         []interface{}
+	Conflict     []*TableField
+	ConflictArgs []interface{}
+	Sets         []*upsertSet
+}
+
+// upsertSet is field changed when record exists, Default resets it to column DEFAULT
+type upsertSet struct {
+	Field   *TableField
+	Default bool
+}
+
+// WithDialect sets sql flavour of db, mysql is default
+func WithDialect(d Dialect) Option {
+	return func(exp *Explorer) {
+		exp.dialect = d
+	}
+}
+
+// conn runs queries built with ? placeholders in dialect of explorer
+type conn struct {
+	q       querier
+	dialect Dialect
+}
+
+func (c *conn) QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error) {
+	return c.q.QueryContext(ctx, c.dialect.rebind(query), args...)
+}
+
+func (c *conn) ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error) {
+	return c.q.ExecContext(ctx, c.dialect.rebind(query), args...)
+}
+
+func (c *conn) QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row {
+	return c.q.QueryRowContext(ctx, c.dialect.rebind(query), args...)
+}
+
+func (exp *Explorer) bind(q querier) querier {
+	return &conn{q: q, dialect: exp.dialect}
+}
+
+func (exp *Explorer) quote(name string) string {
+	return exp.dialect.QuoteIdent(name)
+}
+
+// rebindNumbered replaces ? outside of string literals and quoted identifiers with $1, $2...
+// Doubled quote inside literal or identifier closes and opens it again, so it is skipped too
+func rebindNumbered(query string) string {
+	if !strings.Contains(query, "?") {
+		return query
+	}
+
+	var b strings.Builder
+	n := 0
+	var quote byte
+	for i := 0; i < len(query); i++ {
+		ch := query[i]
+		switch {
+		case quote != 0:
+			if ch == quote {
+				quote = 0
+			}
+		case ch == '\'' || ch == '"':
+			quote = ch
+		case ch == '?':
+			n++
+			b.WriteString("$" + strconv.Itoa(n))
+			continue
+		}
+		b.WriteByte(ch)
+	}
+
+	return b.String()
+}
+
+// defaultValue is column default the way mysql shows it: nil for NULL, string literal
+// without quotes, other expressions as is
+func defaultValue(expr string) *string {
+	expr = strings.TrimSpace(expr)
+	if strings.EqualFold(expr, "NULL") {
+		return nil
+	}
+
+	if end := literalEnd(expr); end == len(expr) {
+		expr = strings.ReplaceAll(expr[1:end-1], "''", "'")
+	}
+
+	return &expr
+}
+
+// literalEnd is length of string literal at start of expr, 0 when expr does not start with one
+func literalEnd(expr string) int {
+	if !strings.HasPrefix(expr, "'") {
+		return 0
+	}
+
+	for i := 1; i < len(expr); i++ {
+		if expr[i] != '\'' {
+			continue
+		}
+		if i+1 < len(expr) && expr[i+1] == '\'' {
+			i++
+			continue
+		}
+		return i + 1
+	}
+
+	return 0
+}
+
+// quoteWith wraps name in quote char, the char inside name is doubled
+func quoteWith(name string, q string) string {
+	return q + strings.ReplaceAll(name, q, q+q) + q
+}
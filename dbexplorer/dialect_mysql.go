@@ -0,0 +1,362 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const primaryIndexName = "PRIMARY"
+
+// mariadb has INSERT ... RETURNING since 10.5
+var mariadbReturningVersion = [2]int{10, 5}
+
+type mysqlDialect struct {
+	mariadb bool
+	version [2]int
+}
+
+// MySQL is dialect of mysql and mariadb, server flavour is detected on Init
+func MySQL() Dialect {
+	return &mysqlDialect{}
+}
+
+func (d *mysqlDialect) Name() string {
+	if d.mariadb {
+		return fmt.Sprintf("mariadb %d.%d", d.version[0], d.version[1])
+	}
+
+	return fmt.Sprintf("mysql %d.%d", d.version[0], d.version[1])
+}
+
+// detect parses VERSION() like 8.0.33 or 10.7.8-MariaDB-1:10.7.8+maria~ubu2004
+func (d *mysqlDialect) detect(ctx context.Context, q querier) error {
+	var version string
+	if err := q.QueryRowContext(ctx, "SELECT VERSION()").Scan(&version); err != nil {
+		return err
+	}
+
+	d.setVersion(version)
+
+	return nil
+}
+
+// setVersion skips 5.5.5- prefix proxies and old mariadb put before real version
+func (d *mysqlDialect) setVersion(version string) {
+	d.mariadb = strings.Contains(strings.ToLower(version), "mariadb")
+	if d.mariadb {
+		version = strings.TrimPrefix(version, "5.5.5-")
+	}
+	d.version = parseVersion(version)
+}
+
+// parseVersion gives major and minor numbers of version string
+func parseVersion(version string) [2]int {
+	parsed := [2]int{}
+
+	parts := strings.SplitN(version, ".", 3)
+	for i := 0; i < len(parts) && i < len(parsed); i++ {
+		digits := strings.TrimLeftFunc(parts[i], func(r rune) bool { return r < '0' || r > '9' })
+		if end := strings.IndexFunc(digits, func(r rune) bool { return r < '0' || r > '9' }); end >= 0 {
+			digits = digits[:end]
+		}
+		parsed[i], _ = strconv.Atoi(digits)
+	}
+
+	return parsed
+}
+
+func versionAtLeast(version, min [2]int) bool {
+	if version[0] != min[0] {
+		return version[0] > min[0]
+	}
+
+	return version[1] >= min[1]
+}
+
+func (d *mysqlDialect) QuoteIdent(name string) string {
+	return quoteWith(name, "`")
+}
+
+func (d *mysqlDialect) rebind(query string) string {
+	return query
+}
+
+func (d *mysqlDialect) tables(ctx context.Context, q querier) ([]string, error) {
+	rows, err := q.QueryContext(ctx, "SHOW TABLES")
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		rows.Scan(&table)
+
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+func (d *mysqlDialect) columns(ctx context.Context, q querier, table string) ([]*TableField, error) {
+	query := fmt.Sprintf("SHOW FULL COLUMNS FROM %s", d.QuoteIdent(table))
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []*TableField{}
+	for rows.Next() {
+		col := column{}
+		rows.Scan(
+			&col.Field,
+			&col.Type,
+			&col.Collation,
+			&col.Null,
+			&col.Key,
+			&col.Default,
+			&col.Extra,
+			&col.Privileges,
+			&col.Comment,
+		)
+
+		fields = append(fields, col.field())
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	d.browseJSONChecks(ctx, q, table, fields)
+
+	return fields, nil
+}
+
+var jsonCheckRe = regexp.MustCompile("(?i)json_valid\\(\\s*`?([^`)]+)`?\\s*\\)")
+
+// browseJSONChecks finds mariadb JSON columns, they are longtext with json_valid() check
+func (d *mysqlDialect) browseJSONChecks(ctx context.Context, q querier, table string, fields []*TableField) {
+	query := "SELECT CHECK_CLAUSE FROM information_schema.CHECK_CONSTRAINTS WHERE CONSTRAINT_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		// server without check constraints
+		return
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var clause string
+		rows.Scan(&clause)
+
+		m := jsonCheckRe.FindStringSubmatch(clause)
+		if m == nil {
+			continue
+		}
+
+		for _, field := range fields {
+			if field.Name == m[1] && field.Type.Kind == KindString {
+				field.Type.Kind = KindJSON
+			}
+		}
+	}
+}
+
+func (d *mysqlDialect) indexes(ctx context.Context, q querier, table string) ([]*indexPart, error) {
+	query := fmt.Sprintf("SHOW INDEX FROM %s", d.QuoteIdent(table))
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	// column set of SHOW INDEX differs between server versions
+	cols, _ := rows.Columns()
+	values := make([]sql.NullString, len(cols))
+	addrs := make([]interface{}, len(cols))
+	for i := range values {
+		addrs[i] = &values[i]
+	}
+
+	parts := []*indexPart{}
+	for rows.Next() {
+		rows.Scan(addrs...)
+
+		row := make(map[string]string, len(cols))
+		for i, col := range cols {
+			row[col] = values[i].String
+		}
+
+		parts = append(parts, &indexPart{
+			Index:   row["Key_name"],
+			Unique:  row["Non_unique"] == "0",
+			Primary: row["Key_name"] == primaryIndexName,
+			Type:    row["Index_type"],
+			// functional indexes have no column
+			Column: row["Column_name"],
+		})
+	}
+
+	return parts, rows.Err()
+}
+
+func (d *mysqlDialect) foreignKeys(ctx context.Context, q querier, table string) ([]*foreignKeyPart, error) {
+	query := `SELECT CONSTRAINT_NAME, COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME
+		FROM information_schema.KEY_COLUMN_USAGE
+		WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ? AND REFERENCED_TABLE_NAME IS NOT NULL
+		ORDER BY CONSTRAINT_NAME, ORDINAL_POSITION`
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []*foreignKeyPart{}
+	for rows.Next() {
+		part := &foreignKeyPart{}
+		rows.Scan(&part.Name, &part.Column, &part.RefTable, &part.RefColumn)
+
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
+
+// checksum sums crc of columns, indexes and foreign keys definitions. TABLES.UPDATE_TIME
+// is not used, it changes on every data write and says nothing about DDL
+func (d *mysqlDialect) checksum(ctx context.Context, q querier) (string, error) {
+	queries := []string{
+		`SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(':', TABLE_NAME, COLUMN_NAME, ORDINAL_POSITION,
+			COLUMN_TYPE, IS_NULLABLE, COLUMN_KEY, COLUMN_DEFAULT, EXTRA))), 0)
+		FROM information_schema.COLUMNS WHERE TABLE_SCHEMA = DATABASE()`,
+		`SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(':', TABLE_NAME, INDEX_NAME, SEQ_IN_INDEX,
+			COLUMN_NAME, NON_UNIQUE, INDEX_TYPE))), 0)
+		FROM information_schema.STATISTICS WHERE TABLE_SCHEMA = DATABASE()`,
+		`SELECT COUNT(*), COALESCE(SUM(CRC32(CONCAT_WS(':', TABLE_NAME, CONSTRAINT_NAME, ORDINAL_POSITION,
+			COLUMN_NAME, REFERENCED_TABLE_NAME, REFERENCED_COLUMN_NAME))), 0)
+		FROM information_schema.KEY_COLUMN_USAGE WHERE TABLE_SCHEMA = DATABASE() AND REFERENCED_TABLE_NAME IS NOT NULL`,
+	}
+
+	checksum := ""
+	for _, query := range queries {
+		var count, sum int64
+		if err := q.QueryRowContext(ctx, query).Scan(&count, &sum); err != nil {
+			return "", err
+		}
+		checksum += fmt.Sprintf("%d:%d;", count, sum)
+	}
+
+	return checksum, nil
+}
+
+func (d *mysqlDialect) estimateCount(ctx context.Context, q querier, table string) (int, error) {
+	var rows sql.NullInt64
+	query := "SELECT TABLE_ROWS FROM information_schema.TABLES WHERE TABLE_SCHEMA = DATABASE() AND TABLE_NAME = ?"
+	if err := q.QueryRowContext(ctx, query, table).Scan(&rows); err != nil {
+		return 0, err
+	}
+
+	return int(rows.Int64), nil
+}
+
+// returning is supported by mariadb only, stock mysql inserts give LastInsertId
+func (d *mysqlDialect) returning() bool {
+	return d.mariadb && versionAtLeast(d.version, mariadbReturningVersion)
+}
+
+func (d *mysqlDialect) insertDefaults(table string) string {
+	return fmt.Sprintf("INSERT INTO %s () VALUES ()", d.QuoteIdent(table))
+}
+
+// upsert runs INSERT ... ON DUPLICATE KEY UPDATE, server reports 1 affected row for insert,
+// 2 for update and 0 when existing record is not changed
+func (d *mysqlDialect) upsert(ctx context.Context, q querier, uq *upsertQuery) (bool, error) {
+	update := make([]string, 0, len(uq.Sets))
+	for _, set := range uq.Sets {
+		name := d.QuoteIdent(set.Field.Name)
+		if set.Default {
+			update = append(update, fmt.Sprintf("%s = DEFAULT(%s)", name, name))
+		} else {
+			update = append(update, fmt.Sprintf("%s = VALUES(%s)", name, name))
+		}
+	}
+	if len(update) == 0 {
+		// nothing to change, still should not fail on duplicate
+		name := d.QuoteIdent(uq.Conflict[0].Name)
+		update = append(update, fmt.Sprintf("%s = %s", name, name))
+	}
+
+	res, err := q.ExecContext(ctx, uq.Insert+" ON DUPLICATE KEY UPDATE "+strings.Join(update, ", "), uq.Args...)
+	if err != nil {
+		return false, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return false, err
+	}
+
+	return affected == 1, nil
+}
+
+// upsertAnyKey is true, ON DUPLICATE KEY UPDATE has no conflict target
+func (d *mysqlDialect) upsertAnyKey() bool {
+	return true
+}
+
+func (d *mysqlDialect) lockedCount(from string) string {
+	return "SELECT COUNT(*) FROM " + from + " FOR UPDATE"
+}
+
+const (
+	errDuplicateEntry  = 1062
+	errRowIsReferenced = 1451
+	errNoReferencedRow = 1452
+	errBadNull         = 1048
+)
+
+// constraint maps server error numbers of integrity violations
+func (d *mysqlDialect) constraint(err error) string {
+	var merr *mysql.MySQLError
+	if !errors.As(err, &merr) {
+		return ""
+	}
+
+	switch merr.Number {
+	case errDuplicateEntry:
+		return RowCodeDuplicate
+	case errRowIsReferenced, errNoReferencedRow:
+		return RowCodeForeignKey
+	case errBadNull:
+		return RowCodeNotNull
+	}
+
+	return ""
+}
+
+// statementTimeout sets mariadb max_statement_time for the query,
+// mysql gets MAX_EXECUTION_TIME optimizer hint which works only for SELECT
+func (d *mysqlDialect) statementTimeout(query string, timeout time.Duration) string {
+	if d.mariadb {
+		return fmt.Sprintf("SET STATEMENT max_statement_time=%g FOR %s", timeout.Seconds(), query)
+	}
+
+	if !strings.HasPrefix(query, "SELECT ") {
+		return query
+	}
+
+	return fmt.Sprintf("SELECT /*+ MAX_EXECUTION_TIME(%d) */ %s", timeout.Milliseconds(), strings.TrimPrefix(query, "SELECT "))
+}
+
+// likeText keeps column as is, server compares any type with LIKE as text
+func (d *mysqlDialect) likeText(column string) string {
+	return column
+}
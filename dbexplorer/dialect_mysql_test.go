@@ -0,0 +1,38 @@
+package dbexplorer
+
+import (
+	"testing"
+)
+
+func TestMySQLVersion(t *testing.T) {
+	cases := []struct {
+		version   string
+		name      string
+		parsed    [2]int
+		returning bool
+	}{
+		{"8.0.33", "mysql 8.0", [2]int{8, 0}, false},
+		{"5.7.44-log", "mysql 5.7", [2]int{5, 7}, false},
+		{"10.4.32-MariaDB", "mariadb 10.4", [2]int{10, 4}, false},
+		{"10.5.0-MariaDB", "mariadb 10.5", [2]int{10, 5}, true},
+		{"10.11.6-MariaDB-1:10.11.6+maria~ubu2204", "mariadb 10.11", [2]int{10, 11}, true},
+		{"11.2.2-MariaDB-log", "mariadb 11.2", [2]int{11, 2}, true},
+		{"5.5.5-10.7.8-MariaDB", "mariadb 10.7", [2]int{10, 7}, true},
+	}
+
+	for _, c := range cases {
+		d := &mysqlDialect{}
+		d.setVersion(c.version)
+
+		if d.version != c.parsed {
+			t.Errorf("%s: parsed %v, want %v", c.version, d.version, c.parsed)
+		}
+		if d.Name() != c.name {
+			t.Errorf("%s: name %s, want %s", c.version, d.Name(), c.name)
+		}
+		// without RETURNING records are inserted one by one by insertEach
+		if d.returning() != c.returning {
+			t.Errorf("%s: returning %v, want %v", c.version, d.returning(), c.returning)
+		}
+	}
+}
@@ -0,0 +1,376 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+type postgresDialect struct{}
+
+// Postgres is dialect of postgresql, tables are read from current schema
+func Postgres() Dialect {
+	return &postgresDialect{}
+}
+
+func (d *postgresDialect) Name() string {
+	return "postgres"
+}
+
+func (d *postgresDialect) QuoteIdent(name string) string {
+	return quoteWith(name, `"`)
+}
+
+func (d *postgresDialect) detect(ctx context.Context, q querier) error {
+	return nil
+}
+
+func (d *postgresDialect) rebind(query string) string {
+	return rebindNumbered(query)
+}
+
+func (d *postgresDialect) tables(ctx context.Context, q querier) ([]string, error) {
+	query := `SELECT table_name FROM information_schema.tables
+		WHERE table_schema = current_schema() AND table_type IN ('BASE TABLE', 'VIEW')
+		ORDER BY table_name`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		rows.Scan(&table)
+
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+type pgColumn struct {
+	Name       string
+	DataType   string
+	UdtName    string
+	Nullable   string
+	Default    sql.NullString
+	Identity   string
+	Generated  string
+	Length     sql.NullInt64
+	Precision  sql.NullInt64
+	Scale      sql.NullInt64
+	Comment    string
+	EnumValues sql.NullString
+}
+
+func (d *postgresDialect) columns(ctx context.Context, q querier, table string) ([]*TableField, error) {
+	query := `SELECT c.column_name, c.data_type, c.udt_name, c.is_nullable, c.column_default,
+			c.is_identity, c.is_generated, c.character_maximum_length, c.numeric_precision, c.numeric_scale,
+			COALESCE(col_description(format('%I.%I', c.table_schema, c.table_name)::regclass, c.ordinal_position), ''),
+			(SELECT string_agg(e.enumlabel, ',' ORDER BY e.enumsortorder)
+				FROM pg_enum e JOIN pg_type t ON t.oid = e.enumtypid
+				WHERE t.typname = c.udt_name)
+		FROM information_schema.columns c
+		WHERE c.table_schema = current_schema() AND c.table_name = $1
+		ORDER BY c.ordinal_position`
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	fields := []*TableField{}
+	for rows.Next() {
+		col := pgColumn{}
+		rows.Scan(
+			&col.Name,
+			&col.DataType,
+			&col.UdtName,
+			&col.Nullable,
+			&col.Default,
+			&col.Identity,
+			&col.Generated,
+			&col.Length,
+			&col.Precision,
+			&col.Scale,
+			&col.Comment,
+			&col.EnumValues,
+		)
+
+		fields = append(fields, col.field())
+	}
+
+	return fields, rows.Err()
+}
+
+// field makes TableField, primary key is marked later from indexes
+func (col *pgColumn) field() *TableField {
+	field := &TableField{
+		Name:            col.Name,
+		Type:            col.columnType(),
+		IsNullable:      col.Nullable == "YES",
+		IsAutoIncrement: col.Identity == "YES" || strings.HasPrefix(col.Default.String, "nextval("),
+		IsGenerated:     col.Generated == "ALWAYS",
+		Comment:         col.Comment,
+	}
+
+	if col.Default.Valid {
+		field.Default = pgDefault(col.Default.String)
+	}
+
+	return field
+}
+
+// pgDefault drops type cast of literal like 'x'::character varying, sequence
+// default is not shown as column is auto increment
+func pgDefault(expr string) *string {
+	if strings.HasPrefix(expr, "nextval(") {
+		return nil
+	}
+
+	if end := literalEnd(expr); end > 0 && strings.HasPrefix(expr[end:], "::") {
+		expr = expr[:end]
+	} else if strings.HasPrefix(strings.ToUpper(expr), "NULL::") {
+		return nil
+	}
+
+	return defaultValue(expr)
+}
+
+// pgTypes maps information_schema data_type to type name and kind, size comes from own columns
+var pgTypes = map[string]ColumnType{
+	"smallint":                    {Name: "smallint", Kind: KindInt},
+	"integer":                     {Name: "integer", Kind: KindInt},
+	"bigint":                      {Name: "bigint", Kind: KindInt},
+	"numeric":                     {Name: "numeric", Kind: KindDecimal},
+	"real":                        {Name: "real", Kind: KindFloat},
+	"double precision":            {Name: "double", Kind: KindFloat},
+	"boolean":                     {Name: "boolean", Kind: KindBool},
+	"date":                        {Name: "date", Kind: KindDate},
+	"timestamp without time zone": {Name: "timestamp", Kind: KindDateTime},
+	"timestamp with time zone":    {Name: "timestamptz", Kind: KindDateTime},
+	"time without time zone":      {Name: "time", Kind: KindTime},
+	"time with time zone":         {Name: "timetz", Kind: KindTime},
+	"json":                        {Name: "json", Kind: KindJSON},
+	"jsonb":                       {Name: "jsonb", Kind: KindJSON},
+	"character varying":           {Name: "varchar", Kind: KindString},
+	"character":                   {Name: "char", Kind: KindString},
+	"text":                        {Name: "text", Kind: KindString},
+	"uuid":                        {Name: "uuid", Kind: KindString, Length: 36},
+	"bytea":                       {Name: "bytea", Kind: KindBlob},
+}
+
+func (col *pgColumn) columnType() *ColumnType {
+	known, ok := pgTypes[col.DataType]
+	if !ok {
+		t := &ColumnType{Name: col.UdtName, Kind: KindString}
+		if col.EnumValues.Valid {
+			t.Kind = KindEnum
+			t.Values = strings.Split(col.EnumValues.String, ",")
+		}
+		return t
+	}
+
+	t := known
+	switch t.Kind {
+	case KindString:
+		if col.Length.Valid {
+			t.Length = int(col.Length.Int64)
+		}
+
+	case KindDecimal:
+		// numeric without precision has no limit, it is left zero
+		t.Precision = int(col.Precision.Int64)
+		t.Scale = int(col.Scale.Int64)
+	}
+
+	return &t
+}
+
+func (d *postgresDialect) indexes(ctx context.Context, q querier, table string) ([]*indexPart, error) {
+	query := `SELECT i.relname, ix.indisunique, ix.indisprimary, am.amname,
+			CASE WHEN ix.indpred IS NULL THEN COALESCE(a.attname, '') ELSE '' END
+		FROM pg_index ix
+		JOIN pg_class t ON t.oid = ix.indrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class i ON i.oid = ix.indexrelid
+		JOIN pg_am am ON am.oid = i.relam
+		CROSS JOIN LATERAL unnest(ix.indkey) WITH ORDINALITY AS k(attnum, ord)
+		LEFT JOIN pg_attribute a ON a.attrelid = t.oid AND a.attnum = k.attnum
+		WHERE n.nspname = current_schema() AND t.relname = $1
+		ORDER BY ix.indisprimary DESC, i.relname, k.ord`
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []*indexPart{}
+	for rows.Next() {
+		part := &indexPart{}
+		// expression parts have zero attnum and partial indexes do not
+		// cover all records, both are left without column
+		rows.Scan(&part.Index, &part.Unique, &part.Primary, &part.Type, &part.Column)
+
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
+
+func (d *postgresDialect) foreignKeys(ctx context.Context, q querier, table string) ([]*foreignKeyPart, error) {
+	query := `SELECT c.conname, a.attname, rt.relname, ra.attname
+		FROM pg_constraint c
+		JOIN pg_class t ON t.oid = c.conrelid
+		JOIN pg_namespace n ON n.oid = t.relnamespace
+		JOIN pg_class rt ON rt.oid = c.confrelid
+		CROSS JOIN LATERAL unnest(c.conkey, c.confkey) WITH ORDINALITY AS k(attnum, refattnum, ord)
+		JOIN pg_attribute a ON a.attrelid = c.conrelid AND a.attnum = k.attnum
+		JOIN pg_attribute ra ON ra.attrelid = c.confrelid AND ra.attnum = k.refattnum
+		WHERE c.contype = 'f' AND n.nspname = current_schema() AND t.relname = $1
+		ORDER BY c.conname, k.ord`
+	rows, err := q.QueryContext(ctx, query, table)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	parts := []*foreignKeyPart{}
+	for rows.Next() {
+		part := &foreignKeyPart{}
+		rows.Scan(&part.Name, &part.Column, &part.RefTable, &part.RefColumn)
+
+		parts = append(parts, part)
+	}
+
+	return parts, rows.Err()
+}
+
+// checksum is md5 of columns, indexes and foreign keys definitions of current schema
+func (d *postgresDialect) checksum(ctx context.Context, q querier) (string, error) {
+	query := `SELECT md5(COALESCE(string_agg(def, ';' ORDER BY def), '')) FROM (
+			SELECT concat_ws(':', table_name, column_name, ordinal_position, data_type, udt_name,
+				is_nullable, column_default, is_identity, is_generated) AS def
+			FROM information_schema.columns WHERE table_schema = current_schema()
+			UNION ALL
+			SELECT concat_ws(':', t.relname, i.relname, pg_get_indexdef(i.oid))
+			FROM pg_index ix
+			JOIN pg_class t ON t.oid = ix.indrelid
+			JOIN pg_class i ON i.oid = ix.indexrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			WHERE n.nspname = current_schema()
+			UNION ALL
+			SELECT concat_ws(':', t.relname, c.conname, pg_get_constraintdef(c.oid))
+			FROM pg_constraint c
+			JOIN pg_class t ON t.oid = c.conrelid
+			JOIN pg_namespace n ON n.oid = t.relnamespace
+			WHERE n.nspname = current_schema() AND c.contype = 'f'
+		) defs`
+
+	var checksum string
+	if err := q.QueryRowContext(ctx, query).Scan(&checksum); err != nil {
+		return "", err
+	}
+
+	return checksum, nil
+}
+
+// estimateCount reads planner statistics, never analyzed table has -1 there
+func (d *postgresDialect) estimateCount(ctx context.Context, q querier, table string) (int, error) {
+	var rows sql.NullFloat64
+	query := `SELECT c.reltuples FROM pg_class c
+		JOIN pg_namespace n ON n.oid = c.relnamespace
+		WHERE n.nspname = current_schema() AND c.relname = $1`
+	if err := q.QueryRowContext(ctx, query, table).Scan(&rows); err != nil {
+		return 0, err
+	}
+
+	if rows.Float64 < 0 {
+		return 0, nil
+	}
+
+	return int(rows.Float64), nil
+}
+
+func (d *postgresDialect) returning() bool {
+	return true
+}
+
+func (d *postgresDialect) insertDefaults(table string) string {
+	return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", d.QuoteIdent(table))
+}
+
+// upsert runs INSERT ... ON CONFLICT, xmax of new row version is zero only for inserted one
+func (d *postgresDialect) upsert(ctx context.Context, q querier, uq *upsertQuery) (bool, error) {
+	var created bool
+	err := q.QueryRowContext(ctx, d.upsertSQL(uq), uq.Args...).Scan(&created)
+	if err == sql.ErrNoRows {
+		// existing record is left as is
+		return false, nil
+	}
+
+	return created, err
+}
+
+func (d *postgresDialect) upsertSQL(uq *upsertQuery) string {
+	conflictNames := make([]string, 0, len(uq.Conflict))
+	for _, f := range uq.Conflict {
+		conflictNames = append(conflictNames, d.QuoteIdent(f.Name))
+	}
+
+	action := "DO NOTHING"
+	if len(uq.Sets) > 0 {
+		update := make([]string, 0, len(uq.Sets))
+		for _, set := range uq.Sets {
+			name := d.QuoteIdent(set.Field.Name)
+			if set.Default {
+				update = append(update, name+" = DEFAULT")
+			} else {
+				update = append(update, fmt.Sprintf("%s = EXCLUDED.%s", name, name))
+			}
+		}
+		action = "DO UPDATE SET " + strings.Join(update, ", ")
+	}
+
+	return fmt.Sprintf("%s ON CONFLICT (%s) %s RETURNING (xmax = 0)", uq.Insert, strings.Join(conflictNames, ", "), action)
+}
+
+func (d *postgresDialect) upsertAnyKey() bool {
+	return false
+}
+
+// lockedCount counts in subquery, postgres does not lock rows of aggregate
+func (d *postgresDialect) lockedCount(from string) string {
+	return "SELECT COUNT(*) FROM (SELECT 1 FROM " + from + " FOR UPDATE) AS matched"
+}
+
+var pgConstraintCodes = map[string]string{
+	"23505": RowCodeDuplicate,
+	"23503": RowCodeForeignKey,
+	"23502": RowCodeNotNull,
+}
+
+// constraint maps integrity violation SQLSTATE, driver is not imported here
+func (d *postgresDialect) constraint(err error) string {
+	var serr interface{ SQLState() string }
+	if !errors.As(err, &serr) {
+		return ""
+	}
+
+	return pgConstraintCodes[serr.SQLState()]
+}
+
+// likeText casts column, postgres has no LIKE for numbers, dates and others
+func (d *postgresDialect) likeText(column string) string {
+	return column + "::text"
+}
+
+// statementTimeout is not supported per query, statement_timeout can be set in connection options
+func (d *postgresDialect) statementTimeout(query string, timeout time.Duration) string {
+	return query
+}
@@ -0,0 +1,168 @@
+package dbexplorer
+
+import (
+	"database/sql"
+	"reflect"
+	"testing"
+)
+
+func TestRebindNumbered(t *testing.T) {
+	cases := []struct {
+		query  string
+		result string
+	}{
+		{"SELECT 1", "SELECT 1"},
+		{`SELECT "id" FROM "items" WHERE "id" = ? AND "title" = ?`, `SELECT "id" FROM "items" WHERE "id" = $1 AND "title" = $2`},
+		{`SELECT * FROM "t" WHERE "what?" = ?`, `SELECT * FROM "t" WHERE "what?" = $1`},
+		{`SELECT * FROM "t" WHERE "it's" = ? AND "b" = ?`, `SELECT * FROM "t" WHERE "it's" = $1 AND "b" = $2`},
+		{`SELECT * FROM "t" WHERE "a""?" = ? AND "b" = ?`, `SELECT * FROM "t" WHERE "a""?" = $1 AND "b" = $2`},
+		{`SELECT * FROM "t" WHERE "a" LIKE ? ESCAPE '!' AND "b" = '?''?' AND "c" = ?`, `SELECT * FROM "t" WHERE "a" LIKE $1 ESCAPE '!' AND "b" = '?''?' AND "c" = $2`},
+	}
+
+	for _, c := range cases {
+		if res := rebindNumbered(c.query); res != c.result {
+			t.Errorf("rebind %s\n got %s\nwant %s", c.query, res, c.result)
+		}
+	}
+}
+
+func TestPgColumnType(t *testing.T) {
+	cases := []struct {
+		col    pgColumn
+		result ColumnType
+	}{
+		{
+			pgColumn{DataType: "character varying", UdtName: "varchar", Length: sql.NullInt64{Int64: 255, Valid: true}},
+			ColumnType{Name: "varchar", Kind: KindString, Length: 255},
+		},
+		{
+			pgColumn{DataType: "text", UdtName: "text"},
+			ColumnType{Name: "text", Kind: KindString},
+		},
+		{
+			pgColumn{DataType: "numeric", UdtName: "numeric", Precision: sql.NullInt64{Int64: 10, Valid: true}, Scale: sql.NullInt64{Int64: 2, Valid: true}},
+			ColumnType{Name: "numeric", Kind: KindDecimal, Precision: 10, Scale: 2},
+		},
+		{
+			pgColumn{DataType: "numeric", UdtName: "numeric"},
+			ColumnType{Name: "numeric", Kind: KindDecimal},
+		},
+		{
+			pgColumn{DataType: "timestamp with time zone", UdtName: "timestamptz"},
+			ColumnType{Name: "timestamptz", Kind: KindDateTime},
+		},
+		{
+			pgColumn{DataType: "uuid", UdtName: "uuid"},
+			ColumnType{Name: "uuid", Kind: KindString, Length: 36},
+		},
+		{
+			pgColumn{DataType: "USER-DEFINED", UdtName: "mood", EnumValues: sql.NullString{String: "sad,ok,happy", Valid: true}},
+			ColumnType{Name: "mood", Kind: KindEnum, Values: []string{"sad", "ok", "happy"}},
+		},
+		{
+			pgColumn{DataType: "USER-DEFINED", UdtName: "geometry"},
+			ColumnType{Name: "geometry", Kind: KindString},
+		},
+	}
+
+	for _, c := range cases {
+		if res := c.col.columnType(); !reflect.DeepEqual(*res, c.result) {
+			t.Errorf("type of %s %s\n got %+v\nwant %+v", c.col.DataType, c.col.UdtName, *res, c.result)
+		}
+	}
+}
+
+func TestPgDefault(t *testing.T) {
+	cases := []struct {
+		col           pgColumn
+		def           interface{}
+		autoIncrement bool
+	}{
+		{pgColumn{Default: sql.NullString{String: "'new'::character varying", Valid: true}}, "new", false},
+		{pgColumn{Default: sql.NullString{String: "'it''s'::text", Valid: true}}, "it's", false},
+		{pgColumn{Default: sql.NullString{String: "'a::b'::text", Valid: true}}, "a::b", false},
+		{pgColumn{Default: sql.NullString{String: "NULL::character varying", Valid: true}}, nil, false},
+		{pgColumn{Default: sql.NullString{String: "0", Valid: true}}, "0", false},
+		{pgColumn{Default: sql.NullString{String: "now()", Valid: true}}, "now()", false},
+		{pgColumn{Default: sql.NullString{String: "nextval('items_id_seq'::regclass)", Valid: true}}, nil, true},
+		{pgColumn{}, nil, false},
+	}
+
+	for _, c := range cases {
+		field := c.col.field()
+
+		var def interface{}
+		if field.Default != nil {
+			def = *field.Default
+		}
+		if def != c.def || field.IsAutoIncrement != c.autoIncrement {
+			t.Errorf("default %s: got %v auto increment %v", c.col.Default.String, def, field.IsAutoIncrement)
+		}
+	}
+}
+
+func TestPostgresUpsertSQL(t *testing.T) {
+	d := &postgresDialect{}
+	id := &TableField{Name: "id"}
+	title := &TableField{Name: "title"}
+	updated := &TableField{Name: "up\"dated"}
+	insert := `INSERT INTO "items" ("id", "title") VALUES (?,?)`
+
+	cases := []struct {
+		uq     *upsertQuery
+		result string
+	}{
+		{
+			&upsertQuery{Table: "items", Insert: insert, Conflict: []*TableField{id}},
+			insert + ` ON CONFLICT ("id") DO NOTHING RETURNING (xmax = 0)`,
+		},
+		{
+			&upsertQuery{Table: "items", Insert: insert, Conflict: []*TableField{id}, Sets: []*upsertSet{{Field: title}, {Field: updated, Default: true}}},
+			insert + ` ON CONFLICT ("id") DO UPDATE SET "title" = EXCLUDED."title", "up""dated" = DEFAULT RETURNING (xmax = 0)`,
+		},
+	}
+
+	for _, c := range cases {
+		if res := d.upsertSQL(c.uq); res != c.result {
+			t.Errorf("upsert\n got %s\nwant %s", res, c.result)
+		}
+	}
+}
+
+func TestPostgresLockedCount(t *testing.T) {
+	d := &postgresDialect{}
+
+	res := d.lockedCount(`"order" WHERE "id" > ?`)
+	want := `SELECT COUNT(*) FROM (SELECT 1 FROM "order" WHERE "id" > ? FOR UPDATE) AS matched`
+	if res != want {
+		t.Errorf("locked count\n got %s\nwant %s", res, want)
+	}
+
+	if rebound := d.rebind(res); rebound != `SELECT COUNT(*) FROM (SELECT 1 FROM "order" WHERE "id" > $1 FOR UPDATE) AS matched` {
+		t.Errorf("locked count is rebound to %s", rebound)
+	}
+}
+
+func TestPostgresLikeCast(t *testing.T) {
+	cases := []struct {
+		d      Dialect
+		field  string
+		result string
+	}{
+		{Postgres(), "id", `"id"::text LIKE ?`},
+		{Postgres(), "title", `"title" LIKE ?`},
+		{MySQL(), "id", "`id` LIKE ?"},
+		{SQLite(), "id", `"id" LIKE ?`},
+	}
+
+	for _, c := range cases {
+		exp := testExplorer(c.d, "title")
+		cond, args, err := exp.buildCondition("order", &Filter{Field: c.field, Op: OpLike, Value: "1%"})
+		if err != nil {
+			t.Fatalf("%s: %s", c.d.Name(), err)
+		}
+		if cond != c.result || len(args) != 1 {
+			t.Errorf("%s: like on %s is %s", c.d.Name(), c.field, cond)
+		}
+	}
+}
@@ -0,0 +1,410 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+type sqliteDialect struct{}
+
+// SQLite is dialect of sqlite database file. Connections of in-memory
+// database do not share data, so it needs shared cache or one connection
+func SQLite() Dialect {
+	return &sqliteDialect{}
+}
+
+func (d *sqliteDialect) Name() string {
+	return "sqlite"
+}
+
+func (d *sqliteDialect) QuoteIdent(name string) string {
+	return quoteWith(name, `"`)
+}
+
+func (d *sqliteDialect) detect(ctx context.Context, q querier) error {
+	return nil
+}
+
+func (d *sqliteDialect) rebind(query string) string {
+	return query
+}
+
+func (d *sqliteDialect) tables(ctx context.Context, q querier) ([]string, error) {
+	query := `SELECT name FROM sqlite_master
+		WHERE type IN ('table', 'view') AND name NOT LIKE 'sqlite!_%' ESCAPE '!'
+		ORDER BY name`
+	rows, err := q.QueryContext(ctx, query)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	tables := []string{}
+	for rows.Next() {
+		var table string
+		rows.Scan(&table)
+
+		tables = append(tables, table)
+	}
+
+	return tables, rows.Err()
+}
+
+type sqliteColumn struct {
+	Cid     int
+	Name    string
+	Type    string
+	NotNull bool
+	Default sql.NullString
+	// Pk is position in primary key starting from 1, 0 for other columns
+	Pk int
+	// Hidden is 2 and 3 for generated columns
+	Hidden int
+}
+
+func (d *sqliteDialect) tableInfo(ctx context.Context, q querier, table string) ([]*sqliteColumn, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA table_xinfo(%s)", d.QuoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	cols := []*sqliteColumn{}
+	for rows.Next() {
+		col := &sqliteColumn{}
+		rows.Scan(&col.Cid, &col.Name, &col.Type, &col.NotNull, &col.Default, &col.Pk, &col.Hidden)
+
+		cols = append(cols, col)
+	}
+
+	return cols, rows.Err()
+}
+
+func (d *sqliteDialect) columns(ctx context.Context, q querier, table string) ([]*TableField, error) {
+	cols, err := d.tableInfo(ctx, q, table)
+	if err != nil {
+		return nil, err
+	}
+
+	pkCount := 0
+	for _, col := range cols {
+		if col.Pk > 0 {
+			pkCount++
+		}
+	}
+
+	fields := make([]*TableField, 0, len(cols))
+	for _, col := range cols {
+		field := &TableField{
+			Name:        col.Name,
+			Type:        sqliteColumnType(col.Type),
+			IsNullable:  !col.NotNull && col.Pk == 0,
+			IsPrimary:   col.Pk > 0,
+			IsGenerated: col.Hidden == 2 || col.Hidden == 3,
+		}
+
+		// single INTEGER PRIMARY KEY is alias of rowid, it is filled by server
+		if pkCount == 1 && col.Pk > 0 && strings.EqualFold(col.Type, "INTEGER") {
+			field.IsAutoIncrement = true
+		}
+
+		if col.Default.Valid {
+			field.Default = defaultValue(col.Default.String)
+		}
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+// sqliteColumnType parses declared type, sqlite does not limit text and blob types without size
+func sqliteColumnType(declared string) *ColumnType {
+	t := ParseColumnType(declared)
+	if !strings.Contains(declared, "(") && (t.Kind == KindString || t.Kind == KindBlob) {
+		t.Length = 0
+	}
+
+	return t
+}
+
+func (d *sqliteDialect) indexes(ctx context.Context, q querier, table string) ([]*indexPart, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA index_list(%s)", d.QuoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	type sqliteIndex struct {
+		name    string
+		unique  bool
+		origin  string
+		partial bool
+	}
+
+	// index columns are read after list is closed
+	list := []*sqliteIndex{}
+	for rows.Next() {
+		var seq int
+		idx := &sqliteIndex{}
+		rows.Scan(&seq, &idx.name, &idx.unique, &idx.origin, &idx.partial)
+
+		list = append(list, idx)
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	parts := []*indexPart{}
+	hasPrimary := false
+	for _, idx := range list {
+		rows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA index_info(%s)", d.QuoteIdent(idx.name)))
+		if err != nil {
+			return nil, err
+		}
+
+		for rows.Next() {
+			var seqno, cid int
+			var column sql.NullString
+			rows.Scan(&seqno, &cid, &column)
+
+			part := &indexPart{
+				Index:   idx.name,
+				Unique:  idx.unique,
+				Primary: idx.origin == "pk",
+			}
+			// expression parts have no column name, partial indexes do not cover all records
+			if !idx.partial {
+				part.Column = column.String
+			}
+
+			parts = append(parts, part)
+		}
+		rows.Close()
+		if err := rows.Err(); err != nil {
+			return nil, err
+		}
+
+		if idx.origin == "pk" {
+			hasPrimary = true
+		}
+	}
+
+	if hasPrimary {
+		return parts, nil
+	}
+
+	// rowid primary key has no index in the list
+	cols, err := d.tableInfo(ctx, q, table)
+	if err != nil {
+		return nil, err
+	}
+
+	pk := []*sqliteColumn{}
+	for _, col := range cols {
+		if col.Pk > 0 {
+			pk = append(pk, col)
+		}
+	}
+	sort.Slice(pk, func(i, j int) bool {
+		return pk[i].Pk < pk[j].Pk
+	})
+
+	primary := make([]*indexPart, 0, len(pk))
+	for _, col := range pk {
+		primary = append(primary, &indexPart{Index: primaryIndexName, Unique: true, Primary: true, Column: col.Name})
+	}
+
+	return append(primary, parts...), nil
+}
+
+// foreignKeys names keys by table and id, sqlite does not keep constraint names
+func (d *sqliteDialect) foreignKeys(ctx context.Context, q querier, table string) ([]*foreignKeyPart, error) {
+	rows, err := q.QueryContext(ctx, fmt.Sprintf("PRAGMA foreign_key_list(%s)", d.QuoteIdent(table)))
+	if err != nil {
+		return nil, err
+	}
+
+	parts := []*foreignKeyPart{}
+	for rows.Next() {
+		var id, seq int
+		var refTable, from string
+		var to, onUpdate, onDelete, match sql.NullString
+		rows.Scan(&id, &seq, &refTable, &from, &to, &onUpdate, &onDelete, &match)
+
+		parts = append(parts, &foreignKeyPart{
+			Name:      fmt.Sprintf("%s_fk_%d", table, id),
+			Column:    from,
+			RefTable:  refTable,
+			RefColumn: to.String,
+		})
+	}
+	rows.Close()
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+
+	// key without referenced columns points to primary key
+	refPrimary := map[string][]string{}
+	seq := map[string]int{}
+	for _, part := range parts {
+		if part.RefColumn != "" {
+			continue
+		}
+
+		pk, ex := refPrimary[part.RefTable]
+		if !ex {
+			cols, err := d.tableInfo(ctx, q, part.RefTable)
+			if err != nil {
+				return nil, err
+			}
+			sort.Slice(cols, func(i, j int) bool {
+				return cols[i].Pk < cols[j].Pk
+			})
+			for _, col := range cols {
+				if col.Pk > 0 {
+					pk = append(pk, col.Name)
+				}
+			}
+			refPrimary[part.RefTable] = pk
+		}
+
+		if i := seq[part.Name]; i < len(pk) {
+			part.RefColumn = pk[i]
+		}
+		seq[part.Name]++
+	}
+
+	return parts, nil
+}
+
+// checksum is schema_version, sqlite increments it on every schema change
+func (d *sqliteDialect) checksum(ctx context.Context, q querier) (string, error) {
+	var version int64
+	if err := q.QueryRowContext(ctx, "PRAGMA schema_version").Scan(&version); err != nil {
+		return "", err
+	}
+
+	return fmt.Sprint(version), nil
+}
+
+// estimateCount counts records, sqlite keeps no row statistics without ANALYZE
+func (d *sqliteDialect) estimateCount(ctx context.Context, q querier, table string) (int, error) {
+	var count int
+	err := q.QueryRowContext(ctx, fmt.Sprintf("SELECT COUNT(*) FROM %s", d.QuoteIdent(table))).Scan(&count)
+
+	return count, err
+}
+
+// returning is not used, keys come from last_insert_rowid
+func (d *sqliteDialect) returning() bool {
+	return false
+}
+
+func (d *sqliteDialect) insertDefaults(table string) string {
+	return fmt.Sprintf("INSERT INTO %s DEFAULT VALUES", d.QuoteIdent(table))
+}
+
+// upsert runs INSERT ... ON CONFLICT. Sqlite reports one changed row both for insert
+// and update, so existence is checked first, writes to database file are serialized anyway
+func (d *sqliteDialect) upsert(ctx context.Context, q querier, uq *upsertQuery) (bool, error) {
+	conflictNames := make([]string, 0, len(uq.Conflict))
+	conditions := make([]string, 0, len(uq.Conflict))
+	for _, f := range uq.Conflict {
+		conflictNames = append(conflictNames, d.QuoteIdent(f.Name))
+		conditions = append(conditions, d.QuoteIdent(f.Name)+" = ?")
+	}
+
+	var exists int
+	query := fmt.Sprintf("SELECT COUNT(*) FROM %s WHERE %s", d.QuoteIdent(uq.Table), strings.Join(conditions, " AND "))
+	if err := q.QueryRowContext(ctx, query, uq.ConflictArgs...).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	action := "DO NOTHING"
+	if len(uq.Sets) > 0 {
+		defaults, err := d.defaultExprs(ctx, q, uq.Table)
+		if err != nil {
+			return false, err
+		}
+
+		update := make([]string, 0, len(uq.Sets))
+		for _, set := range uq.Sets {
+			name := d.QuoteIdent(set.Field.Name)
+			if !set.Default {
+				update = append(update, fmt.Sprintf("%s = excluded.%s", name, name))
+				continue
+			}
+
+			// DEFAULT is not allowed in SET, default expression is taken from table as is
+			def, ex := defaults[set.Field.Name]
+			if !ex {
+				def = "NULL"
+			}
+			update = append(update, fmt.Sprintf("%s = %s", name, def))
+		}
+		action = "DO UPDATE SET " + strings.Join(update, ", ")
+	}
+
+	query = fmt.Sprintf("%s ON CONFLICT (%s) %s", uq.Insert, strings.Join(conflictNames, ", "), action)
+	if _, err := q.ExecContext(ctx, query, uq.Args...); err != nil {
+		return false, err
+	}
+
+	return exists == 0, nil
+}
+
+// defaultExprs are sql expressions of column defaults, schema keeps only their values
+func (d *sqliteDialect) defaultExprs(ctx context.Context, q querier, table string) (map[string]string, error) {
+	cols, err := d.tableInfo(ctx, q, table)
+	if err != nil {
+		return nil, err
+	}
+
+	defaults := make(map[string]string, len(cols))
+	for _, col := range cols {
+		if col.Default.Valid {
+			defaults[col.Name] = col.Default.String
+		}
+	}
+
+	return defaults, nil
+}
+
+func (d *sqliteDialect) upsertAnyKey() bool {
+	return false
+}
+
+// lockedCount needs no lock, sqlite has one writer at a time
+func (d *sqliteDialect) lockedCount(from string) string {
+	return "SELECT COUNT(*) FROM " + from
+}
+
+// constraint reads message of sqlite error like "UNIQUE constraint failed: items.id",
+// its codes are in cgo driver which is not imported here
+func (d *sqliteDialect) constraint(err error) string {
+	msg := err.Error()
+	switch {
+	case strings.Contains(msg, "UNIQUE constraint failed"), strings.Contains(msg, "PRIMARY KEY constraint failed"):
+		return RowCodeDuplicate
+	case strings.Contains(msg, "FOREIGN KEY constraint failed"):
+		return RowCodeForeignKey
+	case strings.Contains(msg, "NOT NULL constraint failed"):
+		return RowCodeNotNull
+	}
+
+	return ""
+}
+
+func (d *sqliteDialect) statementTimeout(query string, timeout time.Duration) string {
+	return query
+}
+
+// likeText keeps column as is, server compares any type with LIKE as text
+func (d *sqliteDialect) likeText(column string) string {
+	return column
+}
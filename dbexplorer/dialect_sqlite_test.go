@@ -0,0 +1,73 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+func TestSQLiteDefaults(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "defaults.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE posts (
+  id INTEGER PRIMARY KEY,
+  title TEXT NOT NULL DEFAULT 'it''s new',
+  body TEXT DEFAULT NULL,
+  views INTEGER NOT NULL DEFAULT 0,
+  created TEXT DEFAULT CURRENT_TIMESTAMP,
+  note TEXT
+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &Explorer{db: db, dialect: SQLite(), schema: newSchema()}
+	ctx := context.Background()
+	if err := exp.ReloadSchema(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	defaults := map[string]interface{}{
+		"id":      nil,
+		"title":   "it's new",
+		"body":    nil,
+		"views":   "0",
+		"created": "CURRENT_TIMESTAMP",
+		"note":    nil,
+	}
+	for _, field := range exp.getTableFields("posts") {
+		var got interface{}
+		if field.Default != nil {
+			got = *field.Default
+		}
+		if got != defaults[field.Name] {
+			t.Errorf("default of %s: got %v, want %v", field.Name, got, defaults[field.Name])
+		}
+	}
+
+	// replace resets missing fields with default expressions of table, not with shown values
+	data := map[string]interface{}{"title": "changed", "body": "text", "views": 5, "note": "n"}
+	if _, err := exp.ReplaceRecord(ctx, "posts", RecordID{"1"}, data); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := exp.ReplaceRecord(ctx, "posts", RecordID{"1"}, map[string]interface{}{"note": "n"}); err != nil {
+		t.Fatal(err)
+	}
+
+	var title string
+	var body sql.NullString
+	var views int
+	if err := db.QueryRow("SELECT title, body, views FROM posts WHERE id = 1").Scan(&title, &body, &views); err != nil {
+		t.Fatal(err)
+	}
+	if title != "it's new" || body.Valid || views != 0 {
+		t.Errorf("replaced record: got %q, %v, %d", title, body, views)
+	}
+}
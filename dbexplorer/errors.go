@@ -3,6 +3,13 @@ package dbexplorer
 import "errors"
 
 var (
-	ErrTableNotFound  = errors.New("unknown table")
-	ErrRecordNotFound = errors.New("record not found")
+	ErrTableNotFound    = errors.New("unknown table")
+	ErrRecordNotFound   = errors.New("record not found")
+	ErrInvalidQuery     = errors.New("invalid query")
+	ErrInvalidID        = errors.New("invalid param: id")
+	ErrReadOnlyTable    = errors.New("table has no primary key, records are read-only")
+	ErrRelationNotFound = errors.New("relation not found")
+	ErrTooManyRecords   = errors.New("too many records affected")
+	ErrDuplicateRecord  = errors.New("record with the same unique key exists")
+	ErrMissingReference = errors.New("record refers to missing record")
 )
@@ -1,47 +1,130 @@
 package dbexplorer
 
 import (
+	"context"
 	"database/sql"
-	"errors"
 	"fmt"
 	"log"
-	"reflect"
-	"strconv"
-	"strings"
+	"sync"
+	"time"
 )
 
 type SqlExplorer interface {
 	GetTables() ([]string, error)
-	GetRecords(table string, offset int, limit int) ([]map[string]interface{}, error)
-	GetRecord(table string, id int) (map[string]interface{}, error)
-	CreateRecord(table string, data map[string]interface{}) (id int, err error)
-	UpdateRecord(table string, id int, data map[string]interface{}) (updated int, err error)
-	DeleteRecord(table string, id int) (deleted int, err error)
+	GetRecords(ctx context.Context, table string, query *RecordsQuery) (*RecordsPage, error)
+	GetRecord(ctx context.Context, table string, id RecordID, fields []string) (map[string]interface{}, error)
+	CreateRecord(ctx context.Context, table string, data map[string]interface{}) (id []interface{}, err error)
+	CreateRecords(ctx context.Context, table string, data []map[string]interface{}, mode BulkMode) (*BulkResult, error)
+	UpdateRecord(ctx context.Context, table string, id RecordID, data map[string]interface{}) (updated int, err error)
+	ReplaceRecord(ctx context.Context, table string, id RecordID, data map[string]interface{}) (created bool, err error)
+	UpsertRecord(ctx context.Context, table string, key string, data map[string]interface{}) (id []interface{}, created bool, err error)
+	DeleteRecord(ctx context.Context, table string, id RecordID) (deleted int, err error)
+	UpdateRecords(ctx context.Context, table string, query *WhereQuery, data map[string]interface{}) (*WriteResult, error)
+	DeleteRecords(ctx context.Context, table string, query *WhereQuery) (*WriteResult, error)
+	Batch(ctx context.Context, ops []*Operation) ([]*OperationResult, error)
+	ExpandRecords(ctx context.Context, table string, records []map[string]interface{}, expand []string) error
+	GetRelatedRecords(ctx context.Context, table string, id RecordID, related string, query *RecordsQuery) (*RecordsPage, error)
+	Aggregate(ctx context.Context, table string, query *AggregateQuery) ([]map[string]interface{}, error)
 	HasTable(table string) bool
 	ValidateCreateData(table string, data map[string]interface{}) error
 	ValidateUpdateData(table string, data map[string]interface{}) error
+	GetSchema() ([]*TableSchema, error)
+	GetTableSchema(table string) (*TableSchema, error)
+	ReloadSchema(ctx context.Context) error
+	WatchSchema(ctx context.Context, interval time.Duration)
 }
 
 type TableField struct {
-	Name       string
-	Type       reflect.Kind
-	IsNullable bool
-	IsPrimary  bool
+	Name            string      `json:"name"`
+	Type            *ColumnType `json:"type"`
+	IsNullable      bool        `json:"nullable"`
+	IsPrimary       bool        `json:"primary"`
+	IsAutoIncrement bool        `json:"auto_increment"`
+	IsGenerated     bool        `json:"generated"`
+	IsOnUpdate      bool        `json:"on_update"`
+	Default         *string     `json:"default"`
+	Comment         string      `json:"comment"`
+}
+
+type RecordsQuery struct {
+	Offset  int
+	Limit   int
+	Filters []*Filter
+	Sort    []*SortField
+	Fields  []string
+	Count   CountMode
+
+	// Search is full-text term, records get relevance in _score
+	Search string
+
+	// WithCursor asks for next page cursor, Cursor continues from previous page
+	WithCursor bool
+	Cursor     string
+}
+
+type RecordsPage struct {
+	Records    []map[string]interface{}
+	NextCursor string
+	Total      *int
 }
 
 type Explorer struct {
-	db          *sql.DB
-	tables      map[string]map[string]*TableField
-	tableFields map[string][]*TableField
-	tableNames  []string
+	db      *sql.DB
+	dialect Dialect
+	options
+
+	// mu guards schema pointer, reloadMu keeps one reload at a time
+	mu       sync.RWMutex
+	reloadMu sync.Mutex
+	schema   *schema
+}
+
+// options are set on creation and shared by snapshots
+type options struct {
+	uniqueKeyFallback bool
+
+	readTimeout        time.Duration
+	writeTimeout       time.Duration
+	statementTimeHints bool
+}
+
+// snapshot is explorer bound to the current schema. Operation takes it once at start
+// and uses it till the end, so schema reload does not mix metadata of two versions
+func (exp *Explorer) snapshot() *Explorer {
+	return &Explorer{
+		db:      exp.db,
+		dialect: exp.dialect,
+		options: exp.options,
+		schema:  exp.current(),
+	}
 }
 
-func NewSqlExplorer(db *sql.DB) SqlExplorer {
+// querier is what *sql.DB and *sql.Tx have in common
+type querier interface {
+	QueryContext(ctx context.Context, query string, args ...interface{}) (*sql.Rows, error)
+	ExecContext(ctx context.Context, query string, args ...interface{}) (sql.Result, error)
+	QueryRowContext(ctx context.Context, query string, args ...interface{}) *sql.Row
+}
+
+type Option func(exp *Explorer)
+
+// WithUniqueKeyFallback lets tables without primary key use
+// NOT NULL unique index as record identity instead of being read-only
+func WithUniqueKeyFallback() Option {
+	return func(exp *Explorer) {
+		exp.uniqueKeyFallback = true
+	}
+}
+
+func NewSqlExplorer(db *sql.DB, opts ...Option) SqlExplorer {
 	exp := &Explorer{
-		db:          db,
-		tables:      make(map[string]map[string]*TableField),
-		tableFields: make(map[string][]*TableField),
-		tableNames:  make([]string, 0),
+		db:      db,
+		dialect: MySQL(),
+		schema:  newSchema(),
+	}
+
+	for _, opt := range opts {
+		opt(exp)
 	}
 
 	exp.Init()
@@ -49,126 +132,231 @@ func NewSqlExplorer(db *sql.DB) SqlExplorer {
 }
 
 func (exp *Explorer) Init() {
-	exp.browseTables()
+	if err := exp.dialect.detect(context.Background(), exp.db); err != nil {
+		log.Fatalln(err)
+	}
 
-	fmt.Printf("\nexplorer inited...\n\n")
+	if err := exp.ReloadSchema(context.Background()); err != nil {
+		log.Fatalln(err)
+	}
+
+	fmt.Printf("\nexplorer inited (%s)...\n\n", exp.dialect.Name())
 }
 
-func (exp *Explorer) browseTables() {
-	rows, err := exp.db.Query("SHOW TABLES")
+func (exp *Explorer) browseTables(ctx context.Context, s *schema) error {
+	// names are collected first to release connection before browsing each table
+	tables, err := exp.dialect.tables(ctx, exp.db)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		var table string
-		rows.Scan(&table)
-
-		exp.tables[table] = make(map[string]*TableField)
-		exp.tableNames = append(exp.tableNames, table)
+	for _, table := range tables {
+		s.tables[table] = make(map[string]*TableField)
+		s.tableNames = append(s.tableNames, table)
+	}
 
-		exp.browseColumns(table)
+	for _, table := range s.tableNames {
+		if err := exp.browseColumns(ctx, s, table); err != nil {
+			return err
+		}
+		if err := exp.browseIndexes(ctx, s, table); err != nil {
+			return err
+		}
+		if err := exp.browseForeignKeys(ctx, s, table); err != nil {
+			return err
+		}
 	}
+
+	return nil
 }
 
-func (exp *Explorer) browseColumns(table string) {
-	query := fmt.Sprintf("SHOW FULL COLUMNS FROM `%s`", table)
-	rows, err := exp.db.Query(query)
+func (exp *Explorer) browseColumns(ctx context.Context, s *schema, table string) error {
+	fields, err := exp.dialect.columns(ctx, exp.db, table)
 	if err != nil {
-		log.Fatalln(err)
+		return err
 	}
-	defer rows.Close()
 
-	for rows.Next() {
-		col := column{}
-		rows.Scan(
-			&col.Field,
-			&col.Type,
-			&col.Collation,
-			&col.Null,
-			&col.Key,
-			&col.Default,
-			&col.Extra,
-			&col.Privileges,
-			&col.Comment,
-		)
-
-		exp.saveField(table, &col)
+	for _, field := range fields {
+		s.saveField(table, field)
 	}
-}
 
-func (exp *Explorer) saveField(table string, col *column) {
-	field := TableField{
-		Name:       col.GetName(),
-		Type:       col.GetType(),
-		IsNullable: col.IsNullable(),
-		IsPrimary:  col.IsPrimary(),
-	}
+	return nil
+}
 
-	exp.tables[table][field.Name] = &field
-	exp.tableFields[table] = append(exp.tableFields[table], &field)
+func (s *schema) saveField(table string, field *TableField) {
+	s.tables[table][field.Name] = field
+	s.tableFields[table] = append(s.tableFields[table], field)
 }
 
 func (exp *Explorer) getField(table string, fieldName string) *TableField {
-	return exp.tables[table][fieldName]
+	return exp.current().getField(table, fieldName)
 }
 
-func (exp *Explorer) getPrimaryKeyField(table string) *TableField {
-	for _, v := range exp.tables[table] {
-		if v.IsPrimary {
-			return v
-		}
-	}
-
-	return nil
+func (exp *Explorer) getTableFields(table string) []*TableField {
+	return exp.current().tableFields[table]
 }
 
 func (exp *Explorer) GetTables() ([]string, error) {
-	return exp.tableNames, nil
+	return exp.current().tableNames, nil
 }
 
 func (exp *Explorer) HasTable(table string) bool {
-	_, has := exp.tables[table]
+	_, has := exp.current().tables[table]
 
 	return has
 }
 
-func (exp *Explorer) GetRecords(table string, offset int, limit int) ([]map[string]interface{}, error) {
+func (exp *Explorer) GetRecords(ctx context.Context, table string, query *RecordsQuery) (*RecordsPage, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opRead)
+	defer cancel()
+
 	if !exp.HasTable(table) {
 		return nil, ErrTableNotFound
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s LIMIT %d OFFSET %d", table, limit, offset)
-	rows, err := exp.db.Query(query)
+	where, args, err := exp.buildWhere(table, query.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	var found *search
+	if query.Search != "" {
+		if query.Cursor != "" {
+			return nil, fmt.Errorf("%w: cursor can not be used with search", ErrInvalidQuery)
+		}
+
+		found, err = exp.buildSearch(table, query.Search)
+		if err != nil {
+			return nil, err
+		}
+
+		if where == "" {
+			where = " WHERE " + found.cond
+		} else {
+			where += " AND " + found.cond
+		}
+		args = append(args, found.condArgs...)
+	}
+
+	order, err := exp.orderFields(table, query.Sort)
+	if err != nil {
+		return nil, err
+	}
+
+	// most relevant first unless sort is asked
+	if found != nil && len(query.Sort) == 0 {
+		order = append([]*orderField{{Field: &TableField{Name: scoreField}, Desc: true}}, order...)
+	}
+
+	total, err := exp.countRecords(ctx, table, query.Count, where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	offset := query.Offset
+	if query.Cursor != "" {
+		seek, seekArgs, err := exp.buildSeek(table, order, query.Cursor)
+		if err != nil {
+			return nil, err
+		}
+
+		if where == "" {
+			where = " WHERE " + seek
+		} else {
+			where += " AND (" + seek + ")"
+		}
+		args = append(args, seekArgs...)
+		offset = 0
+	}
+
+	fields, err := exp.selectFields(table, query.Fields)
+	if err != nil {
+		return nil, err
+	}
+
+	withCursor := query.WithCursor && query.Limit > 0 && found == nil && exp.canUseCursor(table, order)
+
+	hidden := []string{}
+	if withCursor {
+		orderTableFields := make([]*TableField, 0, len(order))
+		for _, of := range order {
+			orderTableFields = append(orderTableFields, of.Field)
+		}
+		fields, hidden = withFields(fields, orderTableFields...)
+	}
+
+	columns := exp.buildColumns(fields)
+	if found != nil {
+		columns += fmt.Sprintf(", %s AS %s", found.score, exp.quote(scoreField))
+		args = append(found.scoreArgs[:len(found.scoreArgs):len(found.scoreArgs)], args...)
+	}
+
+	tail := fmt.Sprintf("%s%s LIMIT %d OFFSET %d", where, exp.buildOrderBy(order), query.Limit, offset)
+	sqlQuery := exp.builder().selectFrom(columns, table, tail)
+	rows, err := exp.bind(exp.db).QueryContext(ctx, exp.readQuery(sqlQuery), args...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	result := exp.scanRecords(table, rows)
+	records, err := exp.scanRecords(table, rows)
+	if err != nil {
+		return nil, err
+	}
+
+	page := &RecordsPage{Records: records, Total: total}
+
+	if withCursor && len(page.Records) == query.Limit {
+		page.NextCursor, _ = encodeCursor(order, page.Records[len(page.Records)-1])
+	}
+
+	for _, rec := range page.Records {
+		for _, name := range hidden {
+			delete(rec, name)
+		}
+		if found != nil {
+			rec[scoreField] = readScore(rec[scoreField])
+		}
+	}
 
-	return result, nil
+	return page, nil
 }
 
-func (exp *Explorer) GetRecord(table string, id int) (map[string]interface{}, error) {
+func (exp *Explorer) GetRecord(ctx context.Context, table string, id RecordID, fields []string) (map[string]interface{}, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opRead)
+	defer cancel()
+
 	if !exp.HasTable(table) {
 		return nil, ErrTableNotFound
 	}
 
-	primaryField := exp.getPrimaryKeyField(table)
-	if primaryField == nil {
-		return nil, ErrRecordNotFound
+	return exp.getRecord(ctx, exp.bind(exp.db), table, id, fields)
+}
+
+func (exp *Explorer) getRecord(ctx context.Context, q querier, table string, id RecordID, fields []string) (map[string]interface{}, error) {
+	keyCond, keyArgs, err := exp.buildKeyCondition(table, id)
+	if err != nil {
+		return nil, err
+	}
+
+	selected, err := exp.selectFields(table, fields)
+	if err != nil {
+		return nil, err
 	}
 
-	query := fmt.Sprintf("SELECT * FROM %s WHERE %s = %d", table, primaryField.Name, id)
-	rows, err := exp.db.Query(query)
+	query := exp.builder().selectFrom(exp.buildColumns(selected), table, " WHERE "+keyCond)
+	rows, err := q.QueryContext(ctx, exp.readQuery(query), keyArgs...)
 	if err != nil {
 		return nil, err
 	}
 	defer rows.Close()
 
-	res := exp.scanRecords(table, rows)
+	res, err := exp.scanRecords(table, rows)
+	if err != nil {
+		return nil, err
+	}
 	if len(res) == 0 {
 		return nil, ErrRecordNotFound
 	}
@@ -176,7 +364,11 @@ func (exp *Explorer) GetRecord(table string, id int) (map[string]interface{}, er
 	return res[0], nil
 }
 
-func (exp *Explorer) CreateRecord(table string, data map[string]interface{}) (id int, err error) {
+func (exp *Explorer) CreateRecord(ctx context.Context, table string, data map[string]interface{}) (id []interface{}, err error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
 	if !exp.HasTable(table) {
 		return id, ErrTableNotFound
 	}
@@ -185,36 +377,23 @@ func (exp *Explorer) CreateRecord(table string, data map[string]interface{}) (id
 		return id, err
 	}
 
-	primaryField := exp.getPrimaryKeyField(table)
-
-	values := []interface{}{}
-
-	fNames := make([]string, 0, len(exp.tableFields[table]))
-	for _, field := range exp.tableFields[table] {
-		if field.IsPrimary {
-			continue
-		}
-
-		val, ex := data[field.Name]
-		if !ex {
-			values = append(values, "")
-		} else {
-			values = append(values, val)
-		}
-
-		fNames = append(fNames, field.Name)
+	if len(exp.getKeyFields(table)) == 0 {
+		return id, ErrReadOnlyTable
 	}
 
-	fieldsPlaceholders := strings.Join(fNames, ",")
-	valuesPlaceholders := strings.TrimSuffix(strings.Repeat("?,", len(values)), ",")
-
-	query := fmt.Sprintf("INSERT INTO %s (%s) VALUES (%s) RETURNING %s", table, fieldsPlaceholders, valuesPlaceholders, primaryField.Name)
-	err = exp.db.QueryRow(query, values...).Scan(&id)
+	ids, err := exp.insertRecords(ctx, exp.bind(exp.db), table, []map[string]interface{}{data})
+	if err != nil {
+		return id, exp.constraintError(err)
+	}
 
-	return id, err
+	return ids[0], nil
 }
 
-func (exp *Explorer) UpdateRecord(table string, id int, data map[string]interface{}) (updated int, err error) {
+func (exp *Explorer) UpdateRecord(ctx context.Context, table string, id RecordID, data map[string]interface{}) (updated int, err error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
 	if !exp.HasTable(table) {
 		return updated, ErrTableNotFound
 	}
@@ -223,119 +402,76 @@ func (exp *Explorer) UpdateRecord(table string, id int, data map[string]interfac
 		return updated, err
 	}
 
-	_, err = exp.GetRecord(table, id)
+	return exp.updateRecord(ctx, exp.bind(exp.db), table, id, data)
+}
+
+// updateRecord writes validated data
+func (exp *Explorer) updateRecord(ctx context.Context, q querier, table string, id RecordID, data map[string]interface{}) (updated int, err error) {
+	_, err = exp.getRecord(ctx, q, table, id, nil)
 	if err != nil {
 		return updated, err
 	}
 
-	primaryField := exp.getPrimaryKeyField(table)
+	keyCond, keyArgs, err := exp.buildKeyCondition(table, id)
+	if err != nil {
+		return updated, err
+	}
 
 	values := []interface{}{}
-	placeholderBuilder := strings.Builder{}
+	fields := []*TableField{}
 	for fname, val := range data {
 		field := exp.getField(table, fname)
 		if field.IsPrimary {
 			continue
 		}
 
-		values = append(values, val)
-		placeholderBuilder.WriteString(fmt.Sprintf("%s = ?,", fname))
+		values = append(values, writeFieldValue(field, val))
+		fields = append(fields, field)
 	}
-	valuesPlaceholder := strings.TrimSuffix(placeholderBuilder.String(), ",")
 
-	query := fmt.Sprintf("UPDATE %s SET %s WHERE %s = %d", table, valuesPlaceholder, primaryField.Name, id)
-	res, err := exp.db.Exec(query, values...)
-	if err != nil {
-		return updated, nil
+	// nothing but key is passed, record exists and stays as is
+	if len(fields) == 0 {
+		return 0, nil
 	}
 
-	affected, err := res.RowsAffected()
-	return int(affected), err
-}
+	values = append(values, keyArgs...)
 
-func (exp *Explorer) DeleteRecord(table string, id int) (deleted int, err error) {
-	if !exp.HasTable(table) {
-		return deleted, ErrTableNotFound
-	}
-
-	primaryField := exp.getPrimaryKeyField(table)
-
-	query := fmt.Sprintf("DELETE FROM %s WHERE %s = %d", table, primaryField.Name, id)
-	res, err := exp.db.Exec(query)
+	query := exp.builder().update(table, fields, " WHERE "+keyCond)
+	res, err := q.ExecContext(ctx, query, values...)
 	if err != nil {
-		return deleted, nil
+		return updated, exp.constraintError(err)
 	}
 
 	affected, err := res.RowsAffected()
 	return int(affected), err
 }
 
-func (exp *Explorer) ValidateCreateData(table string, data map[string]interface{}) error {
-	for _, field := range exp.tableFields[table] {
-		if field.IsPrimary {
-			continue
-		}
-
-		val, ex := data[field.Name]
-
-		if !field.IsNullable && !ex {
-			return errors.New("need required field " + field.Name)
-		}
-
-		errMsg := fmt.Sprintf("field %s have invalid type", field.Name)
-
-		if !field.IsNullable && val == nil {
-			return errors.New(errMsg)
-		} else if val == nil {
-			continue
-		}
-
-		refValue := reflect.ValueOf(val)
-		if field.Type != refValue.Kind() {
-			return errors.New(errMsg)
-		}
-	}
+func (exp *Explorer) DeleteRecord(ctx context.Context, table string, id RecordID) (deleted int, err error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
 
-	for fname := range data {
-		field := exp.getField(table, fname)
-		if field == nil {
-			return errors.New("undefined field: " + fname)
-		}
-
-		if field.IsPrimary {
-			continue
-		}
+	if !exp.HasTable(table) {
+		return deleted, ErrTableNotFound
 	}
 
-	return nil
+	return exp.deleteRecord(ctx, exp.bind(exp.db), table, id)
 }
 
-func (exp *Explorer) ValidateUpdateData(table string, data map[string]interface{}) error {
-	for fname, val := range data {
-		field := exp.getField(table, fname)
-		if field == nil {
-			return errors.New("undefined field: " + fname)
-		}
-
-		errMsg := fmt.Sprintf("field %s have invalid type", field.Name)
-
-		if field.IsPrimary {
-			return errors.New(errMsg)
-		}
-
-		if !field.IsNullable && val == nil {
-			return errors.New(errMsg)
-		} else if val == nil {
-			continue
-		}
+func (exp *Explorer) deleteRecord(ctx context.Context, q querier, table string, id RecordID) (deleted int, err error) {
+	keyCond, keyArgs, err := exp.buildKeyCondition(table, id)
+	if err != nil {
+		return deleted, err
+	}
 
-		refValue := reflect.ValueOf(val)
-		if field.Type != refValue.Kind() {
-			return errors.New(errMsg)
-		}
+	query := exp.builder().deleteFrom(table, " WHERE "+keyCond)
+	res, err := q.ExecContext(ctx, query, keyArgs...)
+	if err != nil {
+		return deleted, err
 	}
 
-	return nil
+	affected, err := res.RowsAffected()
+	return int(affected), err
 }
 
 type recordField struct {
@@ -347,7 +483,8 @@ type record struct {
 	Fields []*recordField
 }
 
-func (exp *Explorer) scanRecords(table string, rows *sql.Rows) []map[string]interface{} {
+// scanRecords reads all rows, error of row or of iteration like deadline in the middle fails whole result
+func (exp *Explorer) scanRecords(table string, rows *sql.Rows) ([]map[string]interface{}, error) {
 	cols, _ := rows.Columns()
 
 	record := record{Fields: make([]*recordField, len(cols))}
@@ -360,12 +497,14 @@ func (exp *Explorer) scanRecords(table string, rows *sql.Rows) []map[string]inte
 
 	result := []map[string]interface{}{}
 	for rows.Next() {
-		rows.Scan(addrs...)
+		if err := rows.Scan(addrs...); err != nil {
+			return nil, err
+		}
 
 		result = append(result, exp.makeRecordMap(table, record))
 	}
 
-	return result
+	return result, rows.Err()
 }
 
 func (exp *Explorer) makeRecordMap(table string, rec record) map[string]interface{} {
@@ -380,31 +519,14 @@ func (exp *Explorer) makeRecordMap(table string, rec record) map[string]interfac
 
 func (exp *Explorer) getRecordFieldValue(table string, f *recordField) interface{} {
 	tField := exp.getField(table, f.Name)
-	fvalue := f.Value.String
-	if tField.IsNullable && fvalue == "" {
+	if !f.Value.Valid {
 		return nil
 	}
 
-	var value interface{}
-
-	switch tField.Type {
-	case reflect.Int:
-		val, err := strconv.Atoi(fvalue)
-		if err != nil {
-			value = fvalue
-		} else {
-			value = val
-		}
-	case reflect.Float64:
-		val, err := strconv.ParseFloat(fvalue, 64)
-		if err != nil {
-			value = fvalue
-		} else {
-			value = val
-		}
-	default:
-		value = fvalue
+	// column is not known yet, schema reload is pending
+	if tField == nil {
+		return f.Value.String
 	}
 
-	return value
+	return readFieldValue(tField, f.Value.String)
 }
@@ -0,0 +1,69 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strings"
+)
+
+// ParseFields builds column list from query param like id,title
+func ParseFields(value string) []string {
+	fields := []string{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name != "" {
+			fields = append(fields, name)
+		}
+	}
+
+	return fields
+}
+
+// selectFields validates requested columns, empty list means all columns
+func (exp *Explorer) selectFields(table string, names []string) ([]*TableField, error) {
+	if len(names) == 0 {
+		return exp.getTableFields(table), nil
+	}
+
+	fields := make([]*TableField, 0, len(names))
+	used := make(map[string]bool, len(names))
+	for _, name := range names {
+		field := exp.getField(table, name)
+		if field == nil {
+			return nil, fmt.Errorf("%w: unknown field %s", ErrInvalidQuery, name)
+		}
+		if used[name] {
+			continue
+		}
+		used[name] = true
+
+		fields = append(fields, field)
+	}
+
+	return fields, nil
+}
+
+func (exp *Explorer) buildColumns(fields []*TableField) string {
+	return exp.builder().columns(fields)
+}
+
+// withFields appends missing fields to selection, returns names of added ones
+func withFields(fields []*TableField, extra ...*TableField) ([]*TableField, []string) {
+	added := []string{}
+	fields = fields[:len(fields):len(fields)]
+	for _, ef := range extra {
+		found := false
+		for _, f := range fields {
+			if f == ef {
+				found = true
+				break
+			}
+		}
+
+		if !found {
+			fields = append(fields, ef)
+			added = append(added, ef.Name)
+		}
+	}
+
+	return fields, added
+}
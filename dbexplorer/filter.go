@@ -0,0 +1,137 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+type FilterOp string
+
+const (
+	OpExact  FilterOp = "exact"
+	OpNot    FilterOp = "ne"
+	OpGt     FilterOp = "gt"
+	OpGte    FilterOp = "gte"
+	OpLt     FilterOp = "lt"
+	OpLte    FilterOp = "lte"
+	OpLike   FilterOp = "like"
+	OpIn     FilterOp = "in"
+	OpIsNull FilterOp = "isnull"
+)
+
+const filterOpSeparator = "__"
+
+var filterOpsSql = map[FilterOp]string{
+	OpExact: "=",
+	OpNot:   "<>",
+	OpGt:    ">",
+	OpGte:   ">=",
+	OpLt:    "<",
+	OpLte:   "<=",
+	OpLike:  "LIKE",
+}
+
+type Filter struct {
+	Field string
+	Op    FilterOp
+	Value string
+}
+
+// ParseFilter builds filter from query param like title=foo, id__gt=10, login__in=a,b
+func ParseFilter(key string, value string) *Filter {
+	filter := &Filter{Field: key, Op: OpExact, Value: value}
+
+	idx := strings.LastIndex(key, filterOpSeparator)
+	if idx <= 0 {
+		return filter
+	}
+
+	op := FilterOp(key[idx+len(filterOpSeparator):])
+	if _, known := filterOpsSql[op]; known || op == OpIn || op == OpIsNull {
+		filter.Field = key[:idx]
+		filter.Op = op
+	}
+
+	return filter
+}
+
+func (exp *Explorer) buildWhere(table string, filters []*Filter) (where string, args []interface{}, err error) {
+	if len(filters) == 0 {
+		return "", nil, nil
+	}
+
+	conditions := make([]string, 0, len(filters))
+	for _, f := range filters {
+		cond, condArgs, err := exp.buildCondition(table, f)
+		if err != nil {
+			return "", nil, err
+		}
+
+		conditions = append(conditions, cond)
+		args = append(args, condArgs...)
+	}
+
+	return " WHERE " + strings.Join(conditions, " AND "), args, nil
+}
+
+// isTextKind tells whether every server compares field with LIKE without cast
+func isTextKind(kind TypeKind) bool {
+	switch kind {
+	case KindString, KindBinary, KindBlob:
+		return true
+	}
+
+	return false
+}
+
+func (exp *Explorer) buildCondition(table string, f *Filter) (string, []interface{}, error) {
+	field := exp.getField(table, f.Field)
+	if field == nil {
+		return "", nil, fmt.Errorf("%w: unknown field %s", ErrInvalidQuery, f.Field)
+	}
+
+	switch f.Op {
+	case OpIsNull:
+		isNull, err := strconv.ParseBool(f.Value)
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s__isnull expects true or false", ErrInvalidQuery, field.Name)
+		}
+
+		if isNull {
+			return exp.quote(field.Name) + " IS NULL", nil, nil
+		}
+		return exp.quote(field.Name) + " IS NOT NULL", nil, nil
+
+	case OpIn:
+		values := strings.Split(f.Value, ",")
+		args := make([]interface{}, 0, len(values))
+		for _, v := range values {
+			arg, err := parseFieldValue(field, v)
+			if err != nil {
+				return "", nil, fmt.Errorf("%w: %s", ErrInvalidQuery, err)
+			}
+			args = append(args, arg)
+		}
+
+		return fmt.Sprintf("%s IN (%s)", exp.quote(field.Name), placeholders(len(args))), args, nil
+
+	case OpLike:
+		if isTextKind(field.Type.Kind) {
+			return exp.builder().cond(field, "LIKE"), []interface{}{f.Value}, nil
+		}
+		return exp.dialect.likeText(exp.quote(field.Name)) + " LIKE ?", []interface{}{f.Value}, nil
+	}
+
+	sqlOp, known := filterOpsSql[f.Op]
+	if !known {
+		return "", nil, fmt.Errorf("%w: unknown filter operator %s", ErrInvalidQuery, f.Op)
+	}
+
+	arg, err := parseFieldValue(field, f.Value)
+	if err != nil {
+		return "", nil, fmt.Errorf("%w: %s", ErrInvalidQuery, err)
+	}
+
+	return exp.builder().cond(field, sqlOp), []interface{}{arg}, nil
+}
@@ -0,0 +1,34 @@
+package dbexplorer
+
+import "context"
+
+// ForeignKey references columns of other table, referenced fields are kept
+// by name because referenced table may be browsed later
+type ForeignKey struct {
+	Name      string
+	Fields    []*TableField
+	RefTable  string
+	RefFields []string
+}
+
+func (exp *Explorer) browseForeignKeys(ctx context.Context, s *schema, table string) error {
+	parts, err := exp.dialect.foreignKeys(ctx, exp.db, table)
+	if err != nil {
+		return err
+	}
+
+	fks := map[string]*ForeignKey{}
+	for _, part := range parts {
+		fk, ex := fks[part.Name]
+		if !ex {
+			fk = &ForeignKey{Name: part.Name, RefTable: part.RefTable}
+			fks[part.Name] = fk
+			s.foreignKeys[table] = append(s.foreignKeys[table], fk)
+		}
+
+		fk.Fields = append(fk.Fields, s.getField(table, part.Column))
+		fk.RefFields = append(fk.RefFields, part.RefColumn)
+	}
+
+	return nil
+}
@@ -0,0 +1,91 @@
+package dbexplorer
+
+import "context"
+
+type Index struct {
+	Name      string
+	IsUnique  bool
+	IsPrimary bool
+	Type      string
+	Fields    []*TableField
+
+	// some key parts are expressions, not columns
+	hasExpression bool
+}
+
+func (exp *Explorer) browseIndexes(ctx context.Context, s *schema, table string) error {
+	parts, err := exp.dialect.indexes(ctx, exp.db, table)
+	if err != nil {
+		return err
+	}
+
+	indexes := map[string]*Index{}
+	for _, part := range parts {
+		idx, ex := indexes[part.Index]
+		if !ex {
+			idx = &Index{
+				Name:      part.Index,
+				IsUnique:  part.Unique,
+				IsPrimary: part.Primary,
+				Type:      part.Type,
+			}
+			indexes[idx.Name] = idx
+			s.indexes[table] = append(s.indexes[table], idx)
+		}
+
+		field := s.getField(table, part.Column)
+		if field == nil {
+			idx.hasExpression = true
+			continue
+		}
+
+		idx.Fields = append(idx.Fields, field)
+		// not every server marks primary key in column list
+		if idx.IsPrimary {
+			field.IsPrimary = true
+		}
+	}
+
+	exp.chooseRecordKey(s, table)
+
+	return nil
+}
+
+// chooseRecordKey picks columns identifying a record: primary key or,
+// when fallback is enabled, the first unique index over NOT NULL columns.
+// Tables without such key are read-only
+func (exp *Explorer) chooseRecordKey(s *schema, table string) {
+	for _, idx := range s.indexes[table] {
+		if idx.IsPrimary {
+			s.recordKeys[table] = idx.Fields
+			return
+		}
+	}
+
+	if !exp.uniqueKeyFallback {
+		return
+	}
+
+	for _, idx := range s.indexes[table] {
+		if !idx.IsUnique || idx.hasExpression || len(idx.Fields) == 0 {
+			continue
+		}
+
+		notNull := true
+		for _, f := range idx.Fields {
+			if f.IsNullable {
+				notNull = false
+				break
+			}
+		}
+
+		if notNull {
+			s.recordKeys[table] = idx.Fields
+			return
+		}
+	}
+}
+
+func (exp *Explorer) getKeyFields(table string) []*TableField {
+	return exp.current().recordKeys[table]
+}
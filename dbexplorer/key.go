@@ -0,0 +1,96 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+const recordIDSeparator = ","
+
+const (
+	// RecordIDInParams is path id of record whose key is in query like ?pk.item_id=1&pk.user_id=2,
+	// so values of composite key may contain separator
+	RecordIDInParams = "_"
+	// RecordKeyParamPrefix starts query params with key values
+	RecordKeyParamPrefix = "pk."
+)
+
+// RecordID is record key values in key columns order, path /table/1,2/ gives [1 2]
+type RecordID []string
+
+func ParseRecordID(value string) RecordID {
+	return strings.Split(value, recordIDSeparator)
+}
+
+// RecordIDFromParams takes pk. params in key columns order, every key column must be passed
+func RecordIDFromParams(keyNames []string, params url.Values) (RecordID, error) {
+	id := make(RecordID, 0, len(keyNames))
+	for _, name := range keyNames {
+		values, ex := params[RecordKeyParamPrefix+name]
+		if !ex || len(values) != 1 {
+			return nil, fmt.Errorf("%w: expect one %s%s param", ErrInvalidID, RecordKeyParamPrefix, name)
+		}
+		id = append(id, values[0])
+	}
+
+	for key := range params {
+		name := strings.TrimPrefix(key, RecordKeyParamPrefix)
+		if name != key && !contains(keyNames, name) {
+			return nil, fmt.Errorf("%w: %s is not key field", ErrInvalidID, name)
+		}
+	}
+
+	return id, nil
+}
+
+func (id RecordID) String() string {
+	return strings.Join(id, recordIDSeparator)
+}
+
+func (exp *Explorer) buildKeyCondition(table string, id RecordID) (string, []interface{}, error) {
+	keyFields := exp.getKeyFields(table)
+	if len(keyFields) == 0 {
+		return "", nil, ErrReadOnlyTable
+	}
+
+	id = joinSingleKey(keyFields, id)
+	if len(id) != len(keyFields) {
+		return "", nil, fmt.Errorf("%w: expect %d key values", ErrInvalidID, len(keyFields))
+	}
+
+	conditions := make([]string, 0, len(keyFields))
+	args := make([]interface{}, 0, len(keyFields))
+	for i, field := range keyFields {
+		val, err := parseFieldValue(field, id[i])
+		if err != nil {
+			return "", nil, fmt.Errorf("%w: %s", ErrInvalidID, err)
+		}
+
+		conditions = append(conditions, exp.builder().cond(field, "="))
+		args = append(args, val)
+	}
+
+	return strings.Join(conditions, " AND "), args, nil
+}
+
+// joinSingleKey takes single column key whole, its value may contain separator
+func joinSingleKey(keyFields []*TableField, id RecordID) RecordID {
+	if len(keyFields) == 1 && len(id) > 1 {
+		return RecordID{strings.Join(id, recordIDSeparator)}
+	}
+
+	return id
+}
+
+// recordKey picks key values from record in key columns order
+func (exp *Explorer) recordKey(table string, rec map[string]interface{}) []interface{} {
+	keyFields := exp.getKeyFields(table)
+
+	key := make([]interface{}, 0, len(keyFields))
+	for _, field := range keyFields {
+		key = append(key, rec[field.Name])
+	}
+
+	return key
+}
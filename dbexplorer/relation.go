@@ -0,0 +1,197 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// relationName is how foreign key is called in expand param: author_id gives author,
+// composite keys are called by constraint name
+func relationName(fk *ForeignKey) string {
+	if len(fk.Fields) != 1 {
+		return fk.Name
+	}
+
+	name := fk.Fields[0].Name
+	if trimmed := strings.TrimSuffix(name, "_id"); trimmed != "" {
+		return trimmed
+	}
+
+	return name
+}
+
+// ParseExpand builds relation list from query param like author,category
+func ParseExpand(value string) []string {
+	return ParseFields(value)
+}
+
+func (exp *Explorer) getRelation(table string, name string) *ForeignKey {
+	for _, fk := range exp.current().foreignKeys[table] {
+		if relationName(fk) == name {
+			return fk
+		}
+	}
+
+	return nil
+}
+
+// ExpandRecords inlines referenced rows under relation name, one query per relation
+func (exp *Explorer) ExpandRecords(ctx context.Context, table string, records []map[string]interface{}, expand []string) error {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opRead)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return ErrTableNotFound
+	}
+
+	for _, name := range expand {
+		fk := exp.getRelation(table, name)
+		if fk == nil {
+			return fmt.Errorf("%w: unknown relation %s", ErrInvalidQuery, name)
+		}
+
+		if err := exp.expandRelation(ctx, name, fk, records); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func (exp *Explorer) expandRelation(ctx context.Context, name string, fk *ForeignKey, records []map[string]interface{}) error {
+	refFields := make([]*TableField, 0, len(fk.RefFields))
+	for _, refName := range fk.RefFields {
+		refField := exp.getField(fk.RefTable, refName)
+		if refField == nil {
+			return ErrTableNotFound
+		}
+		refFields = append(refFields, refField)
+	}
+
+	conditions := []string{}
+	args := []interface{}{}
+	seen := map[string]bool{}
+	for _, rec := range records {
+		values, err := relationValues(fk.Fields, rec)
+		if err != nil {
+			return err
+		}
+		if values == nil || seen[relationKey(values)] {
+			continue
+		}
+		seen[relationKey(values)] = true
+
+		cond := make([]string, 0, len(refFields))
+		for i, refField := range refFields {
+			arg, err := parseFieldValue(refField, values[i])
+			if err != nil {
+				return err
+			}
+			cond = append(cond, exp.builder().cond(refField, "="))
+			args = append(args, arg)
+		}
+		conditions = append(conditions, "("+strings.Join(cond, " AND ")+")")
+	}
+
+	related := map[string]map[string]interface{}{}
+	if len(conditions) > 0 {
+		query := exp.builder().selectFrom("*", fk.RefTable, " WHERE "+strings.Join(conditions, " OR "))
+		rows, err := exp.bind(exp.db).QueryContext(ctx, exp.readQuery(query), args...)
+		if err != nil {
+			return err
+		}
+		defer rows.Close()
+
+		found, err := exp.scanRecords(fk.RefTable, rows)
+		if err != nil {
+			return err
+		}
+
+		for _, row := range found {
+			values, _ := relationValues(refFields, row)
+			related[relationKey(values)] = row
+		}
+	}
+
+	for _, rec := range records {
+		values, _ := relationValues(fk.Fields, rec)
+		if values == nil {
+			rec[name] = nil
+			continue
+		}
+
+		if row, ex := related[relationKey(values)]; ex {
+			rec[name] = row
+		} else {
+			rec[name] = nil
+		}
+	}
+
+	return nil
+}
+
+// relationValues gives key columns of record as strings, nil when some of them is NULL
+func relationValues(fields []*TableField, rec map[string]interface{}) ([]string, error) {
+	values := make([]string, 0, len(fields))
+	for _, f := range fields {
+		val, ex := rec[f.Name]
+		if !ex {
+			return nil, fmt.Errorf("%w: expand needs field %s", ErrInvalidQuery, f.Name)
+		}
+		if val == nil {
+			return nil, nil
+		}
+		values = append(values, fmt.Sprint(val))
+	}
+
+	return values, nil
+}
+
+func relationKey(values []string) string {
+	return strings.Join(values, recordIDSeparator)
+}
+
+// GetRelatedRecords lists rows of related table referencing record by foreign key.
+// When there are several such keys the first one by name is used
+func (exp *Explorer) GetRelatedRecords(ctx context.Context, table string, id RecordID, related string, query *RecordsQuery) (*RecordsPage, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opRead)
+	defer cancel()
+
+	if !exp.HasTable(table) || !exp.HasTable(related) {
+		return nil, ErrTableNotFound
+	}
+
+	var fk *ForeignKey
+	for _, candidate := range exp.current().foreignKeys[related] {
+		if candidate.RefTable == table {
+			fk = candidate
+			break
+		}
+	}
+	if fk == nil {
+		return nil, fmt.Errorf("%w: %s does not reference %s", ErrRelationNotFound, related, table)
+	}
+
+	parent, err := exp.GetRecord(ctx, table, id, fk.RefFields)
+	if err != nil {
+		return nil, err
+	}
+
+	filters := make([]*Filter, 0, len(query.Filters)+len(fk.Fields))
+	filters = append(filters, query.Filters...)
+	for i, f := range fk.Fields {
+		val := parent[fk.RefFields[i]]
+		if val == nil {
+			return &RecordsPage{Records: []map[string]interface{}{}}, nil
+		}
+		filters = append(filters, &Filter{Field: f.Name, Op: OpExact, Value: fmt.Sprint(val)})
+	}
+
+	childQuery := *query
+	childQuery.Filters = filters
+
+	return exp.GetRecords(ctx, related, &childQuery)
+}
@@ -0,0 +1,110 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"time"
+)
+
+// schema is tables metadata read from database. It is never changed
+// after browsing, reload builds a new one and swaps it
+type schema struct {
+	tables      map[string]map[string]*TableField
+	tableFields map[string][]*TableField
+	tableNames  []string
+	indexes     map[string][]*Index
+	recordKeys  map[string][]*TableField
+	foreignKeys map[string][]*ForeignKey
+	checksum    string
+}
+
+func newSchema() *schema {
+	return &schema{
+		tables:      make(map[string]map[string]*TableField),
+		tableFields: make(map[string][]*TableField),
+		tableNames:  make([]string, 0),
+		indexes:     make(map[string][]*Index),
+		recordKeys:  make(map[string][]*TableField),
+		foreignKeys: make(map[string][]*ForeignKey),
+	}
+}
+
+func (s *schema) getField(table string, fieldName string) *TableField {
+	return s.tables[table][fieldName]
+}
+
+func (exp *Explorer) current() *schema {
+	exp.mu.RLock()
+	defer exp.mu.RUnlock()
+
+	return exp.schema
+}
+
+func (exp *Explorer) browseSchema(ctx context.Context) (*schema, error) {
+	s := newSchema()
+
+	checksum, err := exp.schemaChecksum(ctx)
+	if err != nil {
+		return nil, err
+	}
+	s.checksum = checksum
+
+	if err := exp.browseTables(ctx, s); err != nil {
+		return nil, err
+	}
+
+	return s, nil
+}
+
+// ReloadSchema reads tables metadata again, operations in progress keep the snapshot they started with
+func (exp *Explorer) ReloadSchema(ctx context.Context) error {
+	exp.reloadMu.Lock()
+	defer exp.reloadMu.Unlock()
+
+	s, err := exp.browseSchema(ctx)
+	if err != nil {
+		return err
+	}
+
+	exp.mu.Lock()
+	exp.schema = s
+	exp.mu.Unlock()
+
+	return nil
+}
+
+// WatchSchema polls schema checksum and reloads schema when it changes
+func (exp *Explorer) WatchSchema(ctx context.Context, interval time.Duration) {
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		checksum, err := exp.schemaChecksum(ctx)
+		if err != nil {
+			log.Println("schema watch:", err)
+			continue
+		}
+
+		if checksum == exp.current().checksum {
+			continue
+		}
+
+		if err := exp.ReloadSchema(ctx); err != nil {
+			log.Println("schema reload:", err)
+			continue
+		}
+
+		fmt.Printf("schema reloaded\n")
+	}
+}
+
+func (exp *Explorer) schemaChecksum(ctx context.Context) (string, error) {
+	return exp.dialect.checksum(ctx, exp.db)
+}
@@ -0,0 +1,33 @@
+package dbexplorer
+
+import (
+	"testing"
+)
+
+func TestSnapshotKeepsSchema(t *testing.T) {
+	old := newSchema()
+	old.tables["items"] = map[string]*TableField{}
+	old.tableNames = []string{"items"}
+	old.saveField("items", &TableField{Name: "title", Type: &ColumnType{Kind: KindString}})
+
+	exp := &Explorer{dialect: MySQL(), schema: old}
+	snap := exp.snapshot()
+
+	// reload swaps schema without title
+	reloaded := newSchema()
+	reloaded.tables["items"] = map[string]*TableField{}
+	reloaded.tableNames = []string{"items"}
+	exp.mu.Lock()
+	exp.schema = reloaded
+	exp.mu.Unlock()
+
+	if snap.getField("items", "title") == nil {
+		t.Errorf("snapshot lost field of schema it started with")
+	}
+	if exp.getField("items", "title") != nil {
+		t.Errorf("explorer still has field of old schema")
+	}
+	if err := snap.ValidateUpdateData("items", map[string]interface{}{"title": "x"}); err != nil {
+		t.Errorf("validation on snapshot: %s", err)
+	}
+}
@@ -0,0 +1,91 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+)
+
+// scoreField is relevance of record found by search, it is added to every record
+const scoreField = "_score"
+
+const fullTextIndexType = "FULLTEXT"
+
+// sqlite has no default LIKE escape, so it is set explicitly. Backslash is not
+// used as it is special in mysql string literals
+var likeEscaper = strings.NewReplacer(`!`, `!!`, `%`, `!%`, `_`, `!_`)
+
+type search struct {
+	cond      string
+	condArgs  []interface{}
+	score     string
+	scoreArgs []interface{}
+}
+
+// buildSearch matches term against FULLTEXT index, tables without one
+// are searched by LIKE over text columns with number of matched columns as score
+func (exp *Explorer) buildSearch(table string, term string) (*search, error) {
+	if fields := exp.fullTextFields(table); len(fields) > 0 {
+		match := fmt.Sprintf("MATCH (%s) AGAINST (? IN NATURAL LANGUAGE MODE)", exp.buildColumns(fields))
+		return &search{
+			cond:      match,
+			condArgs:  []interface{}{term},
+			score:     match,
+			scoreArgs: []interface{}{term},
+		}, nil
+	}
+
+	pattern := "%" + likeEscaper.Replace(term) + "%"
+
+	likes := []string{}
+	args := []interface{}{}
+	for _, field := range exp.getTableFields(table) {
+		if field.Type.Kind != KindString {
+			continue
+		}
+
+		likes = append(likes, exp.builder().cond(field, "LIKE")+" ESCAPE '!'")
+		args = append(args, pattern)
+	}
+
+	if len(likes) == 0 {
+		return nil, fmt.Errorf("%w: table has no text fields to search", ErrInvalidQuery)
+	}
+
+	// not every server sums booleans
+	scores := make([]string, 0, len(likes))
+	for _, like := range likes {
+		scores = append(scores, "CASE WHEN "+like+" THEN 1 ELSE 0 END")
+	}
+
+	return &search{
+		cond:      "(" + strings.Join(likes, " OR ") + ")",
+		condArgs:  args,
+		score:     strings.Join(scores, " + "),
+		scoreArgs: args,
+	}, nil
+}
+
+// fullTextFields gives columns of the first FULLTEXT index
+func (exp *Explorer) fullTextFields(table string) []*TableField {
+	for _, idx := range exp.current().indexes[table] {
+		if idx.Type == fullTextIndexType && !idx.hasExpression {
+			return idx.Fields
+		}
+	}
+
+	return nil
+}
+
+func readScore(value interface{}) interface{} {
+	str, ok := value.(string)
+	if !ok {
+		return value
+	}
+
+	if score, err := strconv.ParseFloat(str, 64); err == nil {
+		return score
+	}
+
+	return value
+}
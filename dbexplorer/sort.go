@@ -0,0 +1,81 @@
+package dbexplorer
+
+import (
+	"fmt"
+	"strings"
+)
+
+type SortField struct {
+	Field string
+	Desc  bool
+}
+
+// ParseSort builds sort fields from query param like -updated,title
+func ParseSort(value string) []*SortField {
+	sort := []*SortField{}
+	for _, name := range strings.Split(value, ",") {
+		name = strings.TrimSpace(name)
+		if name == "" {
+			continue
+		}
+
+		sf := &SortField{Field: name}
+		if strings.HasPrefix(name, "-") {
+			sf.Field = name[1:]
+			sf.Desc = true
+		}
+
+		sort = append(sort, sf)
+	}
+
+	return sort
+}
+
+type orderField struct {
+	Field *TableField
+	Desc  bool
+}
+
+// orderFields always ends with primary key, so pagination is deterministic
+func (exp *Explorer) orderFields(table string, sort []*SortField) ([]*orderField, error) {
+	fields := make([]*orderField, 0, len(sort)+1)
+	used := make(map[string]bool, len(sort))
+
+	for _, sf := range sort {
+		field := exp.getField(table, sf.Field)
+		if field == nil {
+			return nil, fmt.Errorf("%w: unknown sort field %s", ErrInvalidQuery, sf.Field)
+		}
+		if used[field.Name] {
+			continue
+		}
+		used[field.Name] = true
+
+		fields = append(fields, &orderField{Field: field, Desc: sf.Desc})
+	}
+
+	for _, keyField := range exp.getKeyFields(table) {
+		if !used[keyField.Name] {
+			fields = append(fields, &orderField{Field: keyField})
+		}
+	}
+
+	return fields, nil
+}
+
+func (exp *Explorer) buildOrderBy(order []*orderField) string {
+	if len(order) == 0 {
+		return ""
+	}
+
+	parts := make([]string, 0, len(order))
+	for _, of := range order {
+		if of.Desc {
+			parts = append(parts, exp.quote(of.Field.Name)+" DESC")
+		} else {
+			parts = append(parts, exp.quote(of.Field.Name)+" ASC")
+		}
+	}
+
+	return " ORDER BY " + strings.Join(parts, ", ")
+}
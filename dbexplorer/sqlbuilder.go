@@ -0,0 +1,77 @@
+package dbexplorer
+
+import (
+	"strings"
+)
+
+// sqlBuilder writes statements for dialect of explorer. Table and field names get into
+// query text only quoted and values only as ? placeholders, so nothing from request
+// can change the statement. Fields are taken from schema, tables are checked by callers
+type sqlBuilder struct {
+	dialect Dialect
+}
+
+func (exp *Explorer) builder() sqlBuilder {
+	return sqlBuilder{dialect: exp.dialect}
+}
+
+func (b sqlBuilder) ident(name string) string {
+	return b.dialect.QuoteIdent(name)
+}
+
+// columns is comma separated list of quoted field names
+func (b sqlBuilder) columns(fields []*TableField) string {
+	names := make([]string, 0, len(fields))
+	for _, f := range fields {
+		names = append(names, b.ident(f.Name))
+	}
+
+	return strings.Join(names, ", ")
+}
+
+// cond compares field with placeholder, like `title` = ?
+func (b sqlBuilder) cond(field *TableField, op string) string {
+	return b.ident(field.Name) + " " + op + " ?"
+}
+
+// equal is condition matching every field to its placeholder
+func (b sqlBuilder) equal(fields []*TableField) string {
+	conds := make([]string, 0, len(fields))
+	for _, f := range fields {
+		conds = append(conds, b.cond(f, "="))
+	}
+
+	return strings.Join(conds, " AND ")
+}
+
+// assign is SET list of fields, values go in the same order
+func (b sqlBuilder) assign(fields []*TableField) string {
+	sets := make([]string, 0, len(fields))
+	for _, f := range fields {
+		sets = append(sets, b.cond(f, "="))
+	}
+
+	return strings.Join(sets, ", ")
+}
+
+func placeholders(n int) string {
+	return strings.TrimSuffix(strings.Repeat("?,", n), ",")
+}
+
+// selectFrom is SELECT of columns expression, tail is WHERE, ORDER BY and others as is
+func (b sqlBuilder) selectFrom(columns string, table string, tail string) string {
+	return "SELECT " + columns + " FROM " + b.ident(table) + tail
+}
+
+// insert is INSERT of tuples, each like (?,?,DEFAULT) for given columns
+func (b sqlBuilder) insert(table string, columns []*TableField, tuples []string) string {
+	return "INSERT INTO " + b.ident(table) + " (" + b.columns(columns) + ") VALUES " + strings.Join(tuples, ",")
+}
+
+func (b sqlBuilder) update(table string, fields []*TableField, tail string) string {
+	return "UPDATE " + b.ident(table) + " SET " + b.assign(fields) + tail
+}
+
+func (b sqlBuilder) deleteFrom(table string, tail string) string {
+	return "DELETE FROM " + b.ident(table) + tail
+}
@@ -0,0 +1,127 @@
+package dbexplorer
+
+import (
+	"regexp"
+	"strconv"
+	"strings"
+	"testing"
+)
+
+// testExplorer has table "order" with integer key id and text field of given name
+func testExplorer(d Dialect, name string) *Explorer {
+	s := newSchema()
+	s.tables["order"] = map[string]*TableField{}
+	s.tableNames = []string{"order"}
+
+	id := &TableField{Name: "id", Type: &ColumnType{Name: "int", Kind: KindInt}, IsPrimary: true, IsAutoIncrement: true}
+	s.saveField("order", id)
+	s.saveField("order", &TableField{Name: name, Type: &ColumnType{Name: "text", Kind: KindString}, IsNullable: true})
+	s.recordKeys["order"] = []*TableField{id}
+
+	return &Explorer{dialect: d, schema: s}
+}
+
+var (
+	quotedRe   = regexp.MustCompile("`(?:[^`]|``)*`|\"(?:[^\"]|\"\")*\"|'(?:[^']|'')*'")
+	numberedRe = regexp.MustCompile(`\$[0-9]+`)
+	// keywords, placeholders and postgres alias of locked subquery
+	sqlAlphabetRe = regexp.MustCompile(`^(?:[A-Z0-9 ?$,()=<>!*]|matched)*$`)
+)
+
+// checkQuery makes sure names of query are only quoted known ones and placeholders match args
+func checkQuery(t *testing.T, d Dialect, query string, args []interface{}, names ...string) {
+	t.Helper()
+
+	known := map[string]bool{}
+	for _, name := range names {
+		known[d.QuoteIdent(name)] = true
+	}
+
+	for _, quoted := range quotedRe.FindAllString(query, -1) {
+		if !strings.HasPrefix(quoted, "'") && !known[quoted] {
+			t.Fatalf("%s: unknown identifier %s in %s", d.Name(), quoted, query)
+		}
+	}
+
+	rest := quotedRe.ReplaceAllString(query, "")
+	if !sqlAlphabetRe.MatchString(rest) {
+		t.Fatalf("%s: unquoted text in %s", d.Name(), query)
+	}
+
+	rebound := quotedRe.ReplaceAllString(d.rebind(query), "")
+	numbered := numberedRe.FindAllString(rebound, -1)
+	if len(numbered) > 0 {
+		if strings.Contains(rebound, "?") {
+			t.Fatalf("%s: placeholder is left after rebind of %s", d.Name(), query)
+		}
+		for i, n := range numbered {
+			if n != "$"+strconv.Itoa(i+1) {
+				t.Fatalf("%s: placeholder %s at %d after rebind of %s", d.Name(), n, i+1, query)
+			}
+		}
+	} else {
+		numbered = strings.Split(rebound, "?")[1:]
+	}
+
+	if len(numbered) != len(args) {
+		t.Fatalf("%s: %d placeholders for %d args in %s", d.Name(), len(numbered), len(args), query)
+	}
+}
+
+// FuzzBuildQuery builds queries on field with any name and any request values, names must
+// reach sql only quoted and numbered placeholders must stay in line with args
+func FuzzBuildQuery(f *testing.F) {
+	for _, seed := range []string{"title", "what?", "it's", `a"b`, "a`b", "unit price", "due-date", "x' OR '1'='1", "$1"} {
+		f.Add(seed, "foo?", "-"+seed)
+	}
+
+	dialects := []Dialect{MySQL(), Postgres(), SQLite()}
+
+	f.Fuzz(func(t *testing.T, name string, value string, sort string) {
+		if name == "" || name == "id" {
+			return
+		}
+
+		for _, d := range dialects {
+			exp := testExplorer(d, name)
+			b := exp.builder()
+			names := []string{"order", "id", name, scoreField}
+
+			filters := []*Filter{}
+			for op := range filterOpsSql {
+				filters = append(filters, &Filter{Field: name, Op: op, Value: value})
+			}
+			filters = append(filters, &Filter{Field: name, Op: OpIn, Value: value + "," + value})
+
+			where, args, err := exp.buildWhere("order", filters)
+			if err != nil {
+				t.Fatalf("%s: %s", d.Name(), err)
+			}
+
+			found, err := exp.buildSearch("order", value)
+			if err != nil {
+				t.Fatalf("%s: %s", d.Name(), err)
+			}
+			where += " AND " + found.cond
+			args = append(args, found.condArgs...)
+
+			order, err := exp.orderFields("order", ParseSort(sort))
+			if err != nil {
+				order, _ = exp.orderFields("order", nil)
+			}
+
+			fields := exp.getTableFields("order")
+			columns := exp.buildColumns(fields) + ", " + found.score + " AS " + exp.quote(scoreField)
+			selectArgs := append(append([]interface{}{}, found.scoreArgs...), args...)
+
+			checkQuery(t, d, b.selectFrom(columns, "order", where+exp.buildOrderBy(order)+" LIMIT 5 OFFSET 0"), selectArgs, names...)
+			checkQuery(t, d, b.selectFrom("COUNT(*)", "order", where), args, names...)
+			checkQuery(t, d, d.lockedCount(exp.quote("order")+where), args, names...)
+
+			updateArgs := append([]interface{}{value}, args...)
+			checkQuery(t, d, b.update("order", fields[1:], where), updateArgs, names...)
+			checkQuery(t, d, b.deleteFrom("order", " WHERE "+b.equal(fields[:1])), []interface{}{value}, names...)
+			checkQuery(t, d, b.insert("order", fields[1:], []string{"(?)", "(DEFAULT)"}), []interface{}{value}, names...)
+		}
+	})
+}
@@ -0,0 +1,82 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+const (
+	// mariadb max_statement_time and mysql max_execution_time errors
+	errStatementTimeout = 1969
+	errQueryTimeout     = 3024
+
+	// postgres query_canceled, statement_timeout ends with it
+	sqlStateQueryCanceled = "57014"
+)
+
+type operationKind int
+
+const (
+	opRead operationKind = iota
+	opWrite
+)
+
+// WithTimeouts limits time of read (list, record, aggregate) and write operations, zero means no limit
+func WithTimeouts(read, write time.Duration) Option {
+	return func(exp *Explorer) {
+		exp.readTimeout = read
+		exp.writeTimeout = write
+	}
+}
+
+// WithStatementTimeHints makes server stop read queries itself after read timeout (mariadb only),
+// cancelled context only drops connection while query keeps running on server
+func WithStatementTimeHints() Option {
+	return func(exp *Explorer) {
+		exp.statementTimeHints = true
+	}
+}
+
+func (exp *Explorer) withTimeout(ctx context.Context, kind operationKind) (context.Context, context.CancelFunc) {
+	timeout := exp.readTimeout
+	if kind == opWrite {
+		timeout = exp.writeTimeout
+	}
+
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+
+	return context.WithTimeout(ctx, timeout)
+}
+
+// readQuery adds statement timeout hint to SELECT
+func (exp *Explorer) readQuery(query string) string {
+	if !exp.statementTimeHints || exp.readTimeout <= 0 {
+		return query
+	}
+
+	return exp.dialect.statementTimeout(query, exp.readTimeout)
+}
+
+// IsTimeout reports whether operation was stopped by deadline or server statement timeout
+func IsTimeout(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var merr *mysql.MySQLError
+	if errors.As(err, &merr) {
+		return merr.Number == errStatementTimeout || merr.Number == errQueryTimeout
+	}
+
+	var serr interface{ SQLState() string }
+	if errors.As(err, &serr) {
+		return serr.SQLState() == sqlStateQueryCanceled
+	}
+
+	return false
+}
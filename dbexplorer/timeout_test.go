@@ -0,0 +1,76 @@
+package dbexplorer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+type sqlStateError string
+
+func (e sqlStateError) Error() string {
+	return "sqlstate " + string(e)
+}
+
+func (e sqlStateError) SQLState() string {
+	return string(e)
+}
+
+func TestIsTimeout(t *testing.T) {
+	cases := []struct {
+		err     error
+		timeout bool
+	}{
+		{context.DeadlineExceeded, true},
+		{fmt.Errorf("read: %w", context.DeadlineExceeded), true},
+		{&mysql.MySQLError{Number: errStatementTimeout}, true},
+		{&mysql.MySQLError{Number: errQueryTimeout}, true},
+		{sqlStateError(sqlStateQueryCanceled), true},
+		{context.Canceled, false},
+		{&mysql.MySQLError{Number: 1062}, false},
+		{sqlStateError("23505"), false},
+		{errors.New("query failed"), false},
+	}
+
+	for _, c := range cases {
+		if got := IsTimeout(c.err); got != c.timeout {
+			t.Errorf("IsTimeout(%v) = %v, want %v", c.err, got, c.timeout)
+		}
+	}
+}
+
+func TestWithTimeout(t *testing.T) {
+	exp := &Explorer{}
+	WithTimeouts(time.Minute, time.Hour)(exp)
+
+	cases := []struct {
+		kind    operationKind
+		timeout time.Duration
+	}{
+		{opRead, time.Minute},
+		{opWrite, time.Hour},
+	}
+
+	for _, c := range cases {
+		ctx, cancel := exp.withTimeout(context.Background(), c.kind)
+		deadline, ok := ctx.Deadline()
+		cancel()
+		if !ok || time.Until(deadline) > c.timeout || time.Until(deadline) < c.timeout-time.Second {
+			t.Errorf("kind %d: deadline in %s, want %s", c.kind, time.Until(deadline), c.timeout)
+		}
+	}
+
+	// zero timeout keeps context without deadline but still cancellable
+	ctx, cancel := (&Explorer{}).withTimeout(context.Background(), opRead)
+	if _, ok := ctx.Deadline(); ok {
+		t.Errorf("zero timeout: context has deadline")
+	}
+	cancel()
+	if !errors.Is(ctx.Err(), context.Canceled) {
+		t.Errorf("zero timeout: cancel does not stop context, got %v", ctx.Err())
+	}
+}
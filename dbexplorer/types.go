@@ -0,0 +1,180 @@
+package dbexplorer
+
+import (
+	"strconv"
+	"strings"
+)
+
+type TypeKind string
+
+const (
+	KindInt      TypeKind = "int"
+	KindDecimal  TypeKind = "decimal"
+	KindFloat    TypeKind = "float"
+	KindBool     TypeKind = "bool"
+	KindBit      TypeKind = "bit"
+	KindDate     TypeKind = "date"
+	KindDateTime TypeKind = "datetime"
+	KindTime     TypeKind = "time"
+	KindYear     TypeKind = "year"
+	KindJSON     TypeKind = "json"
+	KindEnum     TypeKind = "enum"
+	KindSet      TypeKind = "set"
+	KindString   TypeKind = "string"
+	KindBinary   TypeKind = "binary"
+	KindBlob     TypeKind = "blob"
+)
+
+var typeKinds = map[string]TypeKind{
+	"tinyint":    KindInt,
+	"smallint":   KindInt,
+	"mediumint":  KindInt,
+	"int":        KindInt,
+	"integer":    KindInt,
+	"bigint":     KindInt,
+	"decimal":    KindDecimal,
+	"numeric":    KindDecimal,
+	"float":      KindFloat,
+	"double":     KindFloat,
+	"real":       KindFloat,
+	"bool":       KindBool,
+	"boolean":    KindBool,
+	"bit":        KindBit,
+	"date":       KindDate,
+	"datetime":   KindDateTime,
+	"timestamp":  KindDateTime,
+	"time":       KindTime,
+	"year":       KindYear,
+	"json":       KindJSON,
+	"enum":       KindEnum,
+	"set":        KindSet,
+	"char":       KindString,
+	"varchar":    KindString,
+	"tinytext":   KindString,
+	"text":       KindString,
+	"mediumtext": KindString,
+	"longtext":   KindString,
+	"binary":     KindBinary,
+	"varbinary":  KindBinary,
+	"tinyblob":   KindBlob,
+	"blob":       KindBlob,
+	"mediumblob": KindBlob,
+	"longblob":   KindBlob,
+}
+
+// max length in bytes for types declared without size
+var typeLengths = map[string]int{
+	"tinytext":   255,
+	"text":       65535,
+	"mediumtext": 16777215,
+	"longtext":   4294967295,
+	"tinyblob":   255,
+	"blob":       65535,
+	"mediumblob": 16777215,
+	"longblob":   4294967295,
+}
+
+type ColumnType struct {
+	// Name is sql type without size and attributes, like varchar or bigint
+	Name     string   `json:"name"`
+	Kind     TypeKind `json:"kind"`
+	Unsigned bool     `json:"unsigned"`
+
+	// Length is max chars for strings, bytes for binary, bits for bit
+	Length int `json:"length,omitempty"`
+
+	// Precision and Scale are set for decimal
+	Precision int `json:"precision,omitempty"`
+	Scale     int `json:"scale,omitempty"`
+
+	// Values are members of enum and set
+	Values []string `json:"values,omitempty"`
+}
+
+// ParseColumnType parses type from SHOW COLUMNS like decimal(10,2) unsigned, enum('a','b')
+func ParseColumnType(sqlType string) *ColumnType {
+	t := &ColumnType{}
+
+	lower := strings.ToLower(sqlType)
+	name := lower
+	args := ""
+	if open := strings.Index(lower, "("); open >= 0 {
+		name = lower[:open]
+		if end := strings.LastIndex(lower, ")"); end > open {
+			args = sqlType[open+1 : end]
+		}
+	} else if sp := strings.Index(lower, " "); sp >= 0 {
+		name = lower[:sp]
+	}
+
+	t.Name = strings.TrimSpace(name)
+	t.Unsigned = strings.Contains(lower, " unsigned")
+
+	kind, known := typeKinds[t.Name]
+	if !known {
+		kind = KindString
+	}
+	t.Kind = kind
+
+	switch t.Kind {
+	case KindEnum, KindSet:
+		t.Values = parseTypeValues(args)
+
+	case KindDecimal:
+		t.Precision, t.Scale = 10, 0
+		if args != "" {
+			parts := strings.Split(args, ",")
+			t.Precision, _ = strconv.Atoi(strings.TrimSpace(parts[0]))
+			if len(parts) > 1 {
+				t.Scale, _ = strconv.Atoi(strings.TrimSpace(parts[1]))
+			}
+		}
+
+	case KindInt:
+		// tinyint(1) is how mysql keeps BOOL
+		if t.Name == "tinyint" && args == "1" {
+			t.Kind = KindBool
+		}
+
+	case KindBit:
+		t.Length = 1
+		if args != "" {
+			t.Length, _ = strconv.Atoi(args)
+		}
+
+	case KindString, KindBinary, KindBlob:
+		if args != "" {
+			t.Length, _ = strconv.Atoi(args)
+		} else {
+			t.Length = typeLengths[t.Name]
+		}
+	}
+
+	return t
+}
+
+// parseTypeValues splits quoted enum members, doubled quote inside is escaped one
+func parseTypeValues(args string) []string {
+	values := []string{}
+
+	var cur strings.Builder
+	inQuote := false
+	for i := 0; i < len(args); i++ {
+		ch := args[i]
+		switch {
+		case ch == '\'' && inQuote && i+1 < len(args) && args[i+1] == '\'':
+			cur.WriteByte('\'')
+			i++
+		case ch == '\'':
+			inQuote = !inQuote
+			if !inQuote {
+				values = append(values, cur.String())
+				cur.Reset()
+			}
+		case inQuote:
+			cur.WriteByte(ch)
+		}
+	}
+
+	return values
+}
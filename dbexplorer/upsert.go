@@ -0,0 +1,266 @@
+package dbexplorer
+
+import (
+	"context"
+	"fmt"
+	"strings"
+)
+
+// ReplaceRecord creates record with key from id or replaces existing one,
+// fields missing in data are reset to their DEFAULT
+func (exp *Explorer) ReplaceRecord(ctx context.Context, table string, id RecordID, data map[string]interface{}) (created bool, err error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return false, ErrTableNotFound
+	}
+
+	_, keyArgs, err := exp.buildKeyCondition(table, id)
+	if err != nil {
+		return false, err
+	}
+
+	if err := exp.validateReplaceData(table, data); err != nil {
+		return false, err
+	}
+
+	keyFields := exp.getKeyFields(table)
+	columns := append([]*TableField{}, keyFields...)
+	values := append([]interface{}{}, keyArgs...)
+	sets := []*upsertSet{}
+
+	for _, field := range exp.getTableFields(table) {
+		if field.IsGenerated || field.IsAutoIncrement || isKeyField(keyFields, field) {
+			continue
+		}
+
+		val, ex := data[field.Name]
+		if !ex {
+			// server keeps ON UPDATE columns itself
+			if !field.IsOnUpdate {
+				sets = append(sets, &upsertSet{Field: field, Default: true})
+			}
+			continue
+		}
+
+		columns = append(columns, field)
+		values = append(values, writeFieldValue(field, val))
+		sets = append(sets, &upsertSet{Field: field})
+	}
+
+	if exp.dialect.upsertAnyKey() {
+		created, err = exp.replaceLocked(ctx, table, keyFields, columns, values, sets)
+	} else {
+		created, err = exp.upsert(ctx, exp.bind(exp.db), table, keyFields, columns, values, sets)
+	}
+
+	// conflict in other unique index must not touch record with another key
+	if err != nil {
+		return false, exp.constraintError(err)
+	}
+
+	return created, nil
+}
+
+// replaceLocked locks record by key, then updates it or inserts new one. It is used when
+// upsert would update record matched by any unique index, duplicate in others fails here
+func (exp *Explorer) replaceLocked(ctx context.Context, table string, keyFields []*TableField, columns []*TableField, values []interface{}, sets []*upsertSet) (bool, error) {
+	tx, err := exp.db.BeginTx(ctx, nil)
+	if err != nil {
+		return false, err
+	}
+	defer tx.Rollback()
+
+	q := exp.bind(tx)
+	b := exp.builder()
+
+	// key fields go first in columns
+	keyArgs := values[:len(keyFields)]
+	where := " WHERE " + b.equal(keyFields)
+
+	var exists int
+	if err := q.QueryRowContext(ctx, exp.dialect.lockedCount(exp.quote(table)+where), keyArgs...).Scan(&exists); err != nil {
+		return false, err
+	}
+
+	if exists == 0 {
+		query := b.insert(table, columns, []string{"(" + placeholders(len(values)) + ")"})
+		if _, err := q.ExecContext(ctx, query, values...); err != nil {
+			return false, err
+		}
+		return true, tx.Commit()
+	}
+
+	if len(sets) == 0 {
+		return false, nil
+	}
+
+	valueOf := make(map[*TableField]interface{}, len(columns))
+	for i, f := range columns {
+		valueOf[f] = values[i]
+	}
+
+	assigns := make([]string, 0, len(sets))
+	args := make([]interface{}, 0, len(sets)+len(keyArgs))
+	for _, set := range sets {
+		if set.Default {
+			assigns = append(assigns, exp.quote(set.Field.Name)+" = DEFAULT")
+			continue
+		}
+		assigns = append(assigns, b.cond(set.Field, "="))
+		args = append(args, valueOf[set.Field])
+	}
+	args = append(args, keyArgs...)
+
+	query := "UPDATE " + exp.quote(table) + " SET " + strings.Join(assigns, ", ") + where
+	if _, err := q.ExecContext(ctx, query, args...); err != nil {
+		return false, err
+	}
+
+	return false, tx.Commit()
+}
+
+// UpsertRecord inserts record or updates fields given in data when record with the same
+// unique key exists. Key is unique index name, empty one means record key.
+// Existing record keeps its record key, key fields in data are used only for insert.
+// Server checks every unique index for duplicates, not only the named one
+func (exp *Explorer) UpsertRecord(ctx context.Context, table string, key string, data map[string]interface{}) (id []interface{}, created bool, err error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return nil, false, ErrTableNotFound
+	}
+
+	if len(exp.getKeyFields(table)) == 0 {
+		return nil, false, ErrReadOnlyTable
+	}
+
+	keyFields, err := exp.uniqueKeyFields(table, key)
+	if err != nil {
+		return nil, false, err
+	}
+
+	if err := exp.ValidateCreateData(table, data); err != nil {
+		return nil, false, err
+	}
+
+	// create validation skips auto increment fields, here they may be the key
+	verr := &ValidationError{}
+	for _, field := range exp.getTableFields(table) {
+		if val, ex := data[field.Name]; ex && field.IsAutoIncrement {
+			if fe := exp.validateValue(field, val); fe != nil {
+				verr.add(fe)
+			}
+		}
+	}
+	if err := verr.orNil(); err != nil {
+		return nil, false, err
+	}
+
+	keyArgs := make([]interface{}, 0, len(keyFields))
+	for _, field := range keyFields {
+		val, ex := data[field.Name]
+		if !ex || val == nil {
+			return nil, false, fmt.Errorf("%w: upsert needs key fields: %s", ErrInvalidQuery, exp.buildColumns(keyFields))
+		}
+
+		keyArgs = append(keyArgs, writeFieldValue(field, val))
+	}
+
+	recordKey := exp.getKeyFields(table)
+	columns := []*TableField{}
+	values := []interface{}{}
+	sets := []*upsertSet{}
+	for _, field := range exp.getTableFields(table) {
+		val, ex := data[field.Name]
+		if !ex || field.IsGenerated {
+			continue
+		}
+
+		columns = append(columns, field)
+		values = append(values, writeFieldValue(field, val))
+		if !isKeyField(keyFields, field) && !isKeyField(recordKey, field) {
+			sets = append(sets, &upsertSet{Field: field})
+		}
+	}
+
+	tx, err := exp.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, false, err
+	}
+	defer tx.Rollback()
+
+	q := exp.bind(tx)
+	created, err = exp.upsert(ctx, q, table, keyFields, columns, values, sets)
+	if err != nil {
+		return nil, false, exp.constraintError(err)
+	}
+
+	// key is read back by unique fields, insert id is not known on update
+	query := exp.builder().selectFrom(exp.buildColumns(recordKey), table, " WHERE "+exp.builder().equal(keyFields))
+	rows, err := q.QueryContext(ctx, query, keyArgs...)
+	if err != nil {
+		return nil, false, err
+	}
+	res, err := exp.scanRecords(table, rows)
+	rows.Close()
+	if err != nil {
+		return nil, false, err
+	}
+	// mysql updated record matched by another unique index, rollback undoes it
+	if len(res) == 0 {
+		return nil, false, ErrDuplicateRecord
+	}
+
+	return exp.recordKey(table, res[0]), created, tx.Commit()
+}
+
+// upsert inserts record or updates sets of existing one with the same conflict key
+func (exp *Explorer) upsert(ctx context.Context, q querier, table string, conflict []*TableField, columns []*TableField, values []interface{}, sets []*upsertSet) (created bool, err error) {
+	uq := &upsertQuery{
+		Table:    table,
+		Insert:   exp.builder().insert(table, columns, []string{"(" + placeholders(len(values)) + ")"}),
+		Args:     values,
+		Conflict: conflict,
+		Sets:     sets,
+	}
+
+	for _, kf := range conflict {
+		for i, f := range columns {
+			if f == kf {
+				uq.ConflictArgs = append(uq.ConflictArgs, values[i])
+			}
+		}
+	}
+
+	return exp.dialect.upsert(ctx, q, uq)
+}
+
+// uniqueKeyFields gives columns of named unique index, empty name means record key
+func (exp *Explorer) uniqueKeyFields(table string, key string) ([]*TableField, error) {
+	if key == "" {
+		return exp.getKeyFields(table), nil
+	}
+
+	for _, idx := range exp.current().indexes[table] {
+		if idx.Name == key && idx.IsUnique && !idx.hasExpression {
+			return idx.Fields, nil
+		}
+	}
+
+	return nil, fmt.Errorf("%w: unknown unique key %s", ErrInvalidQuery, key)
+}
+
+func isKeyField(keyFields []*TableField, field *TableField) bool {
+	for _, kf := range keyFields {
+		if kf == field {
+			return true
+		}
+	}
+
+	return false
+}
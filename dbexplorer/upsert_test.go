@@ -0,0 +1,88 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"path/filepath"
+	"testing"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// anyKeyDialect is sqlite with upsert conflicting on any unique index, like mysql
+type anyKeyDialect struct {
+	Dialect
+}
+
+func (anyKeyDialect) upsertAnyKey() bool {
+	return true
+}
+
+func TestReplaceLockedKeepsOtherKeys(t *testing.T) {
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(t.TempDir(), "replace.db"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE TABLE accounts (
+  id INTEGER PRIMARY KEY,
+  login TEXT NOT NULL UNIQUE,
+  email TEXT NOT NULL UNIQUE
+)`)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	exp := &Explorer{db: db, dialect: anyKeyDialect{SQLite()}, schema: newSchema()}
+	ctx := context.Background()
+	if err := exp.ReloadSchema(ctx); err != nil {
+		t.Fatal(err)
+	}
+
+	cases := []struct {
+		id      string
+		login   string
+		email   string
+		created bool
+		err     error
+	}{
+		{"1", "rvasily", "rvasily@example.com", true, nil},
+		{"5", "rvasily", "other@example.com", false, ErrDuplicateRecord},
+		{"5", "other", "other@example.com", true, nil},
+		{"5", "other", "rvasily@example.com", false, ErrDuplicateRecord},
+		{"5", "renamed", "other@example.com", false, nil},
+	}
+
+	for _, c := range cases {
+		data := map[string]interface{}{"login": c.login, "email": c.email}
+		created, err := exp.ReplaceRecord(ctx, "accounts", RecordID{c.id}, data)
+		if !errors.Is(err, c.err) || created != c.created {
+			t.Fatalf("replace %s %s: got %v, %v", c.id, c.login, created, err)
+		}
+	}
+
+	rows, err := db.Query("SELECT id, login, email FROM accounts ORDER BY id")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer rows.Close()
+
+	got := [][3]string{}
+	for rows.Next() {
+		var r [3]string
+		if err := rows.Scan(&r[0], &r[1], &r[2]); err != nil {
+			t.Fatal(err)
+		}
+		got = append(got, r)
+	}
+
+	want := [][3]string{
+		{"1", "rvasily", "rvasily@example.com"},
+		{"5", "renamed", "other@example.com"},
+	}
+	if len(got) != len(want) || got[0] != want[0] || got[1] != want[1] {
+		t.Fatalf("got records %v, want %v", got, want)
+	}
+}
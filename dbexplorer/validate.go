@@ -0,0 +1,430 @@
+package dbexplorer
+
+import (
+	"encoding/base64"
+	"fmt"
+	"math"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+)
+
+const (
+	CodeRequired      = "required"
+	CodeUnknownField  = "unknown_field"
+	CodeInvalidType   = "invalid_type"
+	CodeReadOnly      = "read_only"
+	CodeGenerated     = "generated"
+	CodeTooLong       = "too_long"
+	CodeOutOfRange    = "out_of_range"
+	CodeTooManyDigits = "too_many_digits"
+	CodeInvalidChoice = "invalid_choice"
+	CodeInvalidFormat = "invalid_format"
+)
+
+type FieldError struct {
+	Field     string      `json:"field"`
+	Code      string      `json:"code"`
+	Min       interface{} `json:"min,omitempty"`
+	Max       interface{} `json:"max,omitempty"`
+	Precision int         `json:"precision,omitempty"`
+	Scale     int         `json:"scale,omitempty"`
+	Allowed   []string    `json:"allowed,omitempty"`
+	Format    string      `json:"format,omitempty"`
+
+	message string
+}
+
+func (fe *FieldError) Error() string {
+	return fe.message
+}
+
+// ValidationError keeps every violation found in record data
+type ValidationError struct {
+	Errors []*FieldError
+}
+
+func (ve *ValidationError) Error() string {
+	msgs := make([]string, 0, len(ve.Errors))
+	for _, fe := range ve.Errors {
+		msgs = append(msgs, fe.Error())
+	}
+
+	return strings.Join(msgs, "; ")
+}
+
+func (ve *ValidationError) add(fe *FieldError) {
+	ve.Errors = append(ve.Errors, fe)
+}
+
+func (ve *ValidationError) orNil() error {
+	if len(ve.Errors) == 0 {
+		return nil
+	}
+
+	return ve
+}
+
+type intRange struct {
+	min int64
+	max int64
+	// umax is max for unsigned, min is 0
+	umax uint64
+}
+
+var intRanges = map[string]intRange{
+	"tinyint":   {math.MinInt8, math.MaxInt8, math.MaxUint8},
+	"smallint":  {math.MinInt16, math.MaxInt16, math.MaxUint16},
+	"mediumint": {-1 << 23, 1<<23 - 1, 1<<24 - 1},
+	"int":       {math.MinInt32, math.MaxInt32, math.MaxUint32},
+	"integer":   {math.MinInt32, math.MaxInt32, math.MaxUint32},
+	"bigint":    {math.MinInt64, math.MaxInt64, math.MaxUint64},
+}
+
+const (
+	dateFormat     = "YYYY-MM-DD"
+	dateTimeFormat = "YYYY-MM-DD hh:mm:ss"
+	timeFormat     = "hh:mm:ss"
+)
+
+var dateTimeLayouts = []string{
+	"2006-01-02 15:04:05.999999",
+	"2006-01-02T15:04:05.999999",
+	"2006-01-02",
+}
+
+var timeRe = regexp.MustCompile(`^-?\d{1,3}:\d{2}(:\d{2}(\.\d{1,6})?)?$`)
+
+func (exp *Explorer) ValidateCreateData(table string, data map[string]interface{}) error {
+	exp = exp.snapshot()
+	verr := &ValidationError{}
+
+	for _, field := range exp.getTableFields(table) {
+		if field.IsAutoIncrement {
+			continue
+		}
+
+		exp.validateCreateField(field, data, verr)
+	}
+
+	exp.validateUnknownFields(table, data, verr)
+
+	return verr.orNil()
+}
+
+// validateReplaceData checks data as whole record, except key fields which come from path
+func (exp *Explorer) validateReplaceData(table string, data map[string]interface{}) error {
+	verr := &ValidationError{}
+
+	keys := map[string]bool{}
+	for _, field := range exp.getKeyFields(table) {
+		keys[field.Name] = true
+	}
+
+	for _, field := range exp.getTableFields(table) {
+		if keys[field.Name] {
+			if _, ex := data[field.Name]; ex {
+				verr.add(readOnly(field))
+			}
+			continue
+		}
+
+		if field.IsAutoIncrement {
+			continue
+		}
+
+		exp.validateCreateField(field, data, verr)
+	}
+
+	exp.validateUnknownFields(table, data, verr)
+
+	return verr.orNil()
+}
+
+func (exp *Explorer) validateCreateField(field *TableField, data map[string]interface{}, verr *ValidationError) {
+	val, ex := data[field.Name]
+	if !ex {
+		if isRequired(field) {
+			verr.add(&FieldError{Field: field.Name, Code: CodeRequired, message: "need required field " + field.Name})
+		}
+		return
+	}
+
+	if field.IsGenerated {
+		verr.add(generated(field))
+		return
+	}
+
+	if fe := exp.validateValue(field, val); fe != nil {
+		verr.add(fe)
+	}
+}
+
+func (exp *Explorer) ValidateUpdateData(table string, data map[string]interface{}) error {
+	exp = exp.snapshot()
+	verr := &ValidationError{}
+
+	for _, field := range exp.getTableFields(table) {
+		val, ex := data[field.Name]
+		if !ex {
+			continue
+		}
+
+		if field.IsPrimary {
+			verr.add(readOnly(field))
+			continue
+		}
+
+		if field.IsGenerated {
+			verr.add(generated(field))
+			continue
+		}
+
+		if fe := exp.validateValue(field, val); fe != nil {
+			verr.add(fe)
+		}
+	}
+
+	exp.validateUnknownFields(table, data, verr)
+
+	return verr.orNil()
+}
+
+// isRequired reports whether server has no value for omitted column
+func isRequired(field *TableField) bool {
+	return !field.IsNullable && field.Default == nil && !field.IsGenerated && !field.IsOnUpdate
+}
+
+func (exp *Explorer) validateUnknownFields(table string, data map[string]interface{}, verr *ValidationError) {
+	unknown := []string{}
+	for fname := range data {
+		if exp.getField(table, fname) == nil {
+			unknown = append(unknown, fname)
+		}
+	}
+	sort.Strings(unknown)
+
+	for _, fname := range unknown {
+		verr.add(&FieldError{Field: fname, Code: CodeUnknownField, message: "undefined field: " + fname})
+	}
+}
+
+func (exp *Explorer) validateValue(field *TableField, val interface{}) *FieldError {
+	if val == nil {
+		if field.IsNullable {
+			return nil
+		}
+		return invalidType(field)
+	}
+
+	if !isValidValue(field.Type, val) {
+		return invalidType(field)
+	}
+
+	t := field.Type
+	switch t.Kind {
+	case KindInt, KindBool:
+		num, _ := numberString(val)
+		if b, ok := val.(bool); ok {
+			num = strconv.FormatInt(boolToInt(b), 10)
+		}
+		return validateIntRange(field, num)
+
+	case KindBit:
+		num, _ := numberString(val)
+		if num == "" {
+			return nil
+		}
+		bits, _ := strconv.ParseUint(num, 10, 64)
+		if t.Length < 64 && bits >= 1<<uint(t.Length) {
+			max := uint64(1)<<uint(t.Length) - 1
+			return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: 0, Max: max,
+				message: fmt.Sprintf("field %s is out of range, max %d", field.Name, max)}
+		}
+
+	case KindYear:
+		str, ok := numberString(val)
+		if !ok {
+			str = val.(string)
+		}
+		year, _ := strconv.Atoi(str)
+		if year != 0 && (year < 1901 || year > 2155) {
+			return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: 1901, Max: 2155,
+				message: fmt.Sprintf("field %s is out of range, min 1901, max 2155", field.Name)}
+		}
+
+	case KindDecimal:
+		num, _ := numberString(val)
+		return validateDecimal(field, num)
+
+	case KindFloat:
+		num, _ := numberString(val)
+		f, _ := strconv.ParseFloat(num, 64)
+		if t.Unsigned && f < 0 {
+			return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: 0,
+				message: fmt.Sprintf("field %s is out of range, min 0", field.Name)}
+		}
+
+	case KindString:
+		str := val.(string)
+		length := utf8.RuneCountInString(str)
+		// text types are limited in bytes, char and varchar in chars
+		if _, inBytes := typeLengths[t.Name]; inBytes {
+			length = len(str)
+		}
+		if t.Length > 0 && length > t.Length {
+			return tooLong(field)
+		}
+
+	case KindBinary:
+		b, _ := parseBinary(val.(string))
+		if t.Length > 0 && len(b) > t.Length {
+			return tooLong(field)
+		}
+
+	case KindBlob:
+		b, _ := base64.StdEncoding.DecodeString(val.(string))
+		if t.Length > 0 && len(b) > t.Length {
+			return tooLong(field)
+		}
+
+	case KindEnum:
+		if !contains(t.Values, val.(string)) {
+			return invalidChoice(field)
+		}
+
+	case KindSet:
+		members := []string{}
+		if list, ok := val.([]interface{}); ok {
+			for _, item := range list {
+				members = append(members, item.(string))
+			}
+		} else if str := val.(string); str != "" {
+			members = strings.Split(str, ",")
+		}
+
+		for _, m := range members {
+			if !contains(t.Values, m) {
+				return invalidChoice(field)
+			}
+		}
+
+	case KindDate:
+		if _, err := time.Parse("2006-01-02", val.(string)); err != nil {
+			return invalidFormat(field, dateFormat)
+		}
+
+	case KindDateTime:
+		if !isDateTime(val.(string)) {
+			return invalidFormat(field, dateTimeFormat)
+		}
+
+	case KindTime:
+		if !timeRe.MatchString(val.(string)) {
+			return invalidFormat(field, timeFormat)
+		}
+	}
+
+	return nil
+}
+
+func validateIntRange(field *TableField, num string) *FieldError {
+	r, known := intRanges[field.Type.Name]
+	if field.Type.Kind == KindBool {
+		r, known = intRanges["tinyint"], true
+	}
+	if !known {
+		return nil
+	}
+
+	if field.Type.Unsigned {
+		if v, err := strconv.ParseUint(num, 10, 64); err == nil && v <= r.umax {
+			return nil
+		}
+		return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: 0, Max: r.umax,
+			message: fmt.Sprintf("field %s is out of range, min 0, max %d", field.Name, r.umax)}
+	}
+
+	if v, err := strconv.ParseInt(num, 10, 64); err == nil && v >= r.min && v <= r.max {
+		return nil
+	}
+	return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: r.min, Max: r.max,
+		message: fmt.Sprintf("field %s is out of range, min %d, max %d", field.Name, r.min, r.max)}
+}
+
+// validateDecimal checks integer part fits, extra fraction digits are rounded by server
+func validateDecimal(field *TableField, num string) *FieldError {
+	t := field.Type
+
+	if t.Unsigned && strings.HasPrefix(num, "-") {
+		return &FieldError{Field: field.Name, Code: CodeOutOfRange, Min: 0,
+			message: fmt.Sprintf("field %s is out of range, min 0", field.Name)}
+	}
+
+	f, _ := strconv.ParseFloat(num, 64)
+	intPart := strconv.FormatFloat(math.Trunc(math.Abs(f)), 'f', 0, 64)
+	intDigits := len(intPart)
+	if intPart == "0" {
+		intDigits = 0
+	}
+
+	// numeric without precision takes any number
+	if t.Precision > 0 && intDigits > t.Precision-t.Scale {
+		return &FieldError{Field: field.Name, Code: CodeTooManyDigits, Precision: t.Precision, Scale: t.Scale,
+			message: fmt.Sprintf("field %s has too many digits, decimal(%d,%d)", field.Name, t.Precision, t.Scale)}
+	}
+
+	return nil
+}
+
+func isDateTime(value string) bool {
+	for _, layout := range dateTimeLayouts {
+		if _, err := time.Parse(layout, value); err == nil {
+			return true
+		}
+	}
+
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, v := range list {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func invalidType(field *TableField) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeInvalidType,
+		message: fmt.Sprintf("field %s have invalid type", field.Name)}
+}
+
+func readOnly(field *TableField) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeReadOnly, message: fmt.Sprintf("field %s is read-only", field.Name)}
+}
+
+func generated(field *TableField) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeGenerated,
+		message: fmt.Sprintf("field %s is generated and can not be written", field.Name)}
+}
+
+func tooLong(field *TableField) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeTooLong, Max: field.Type.Length,
+		message: fmt.Sprintf("field %s is too long, max %d", field.Name, field.Type.Length)}
+}
+
+func invalidChoice(field *TableField) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeInvalidChoice, Allowed: field.Type.Values,
+		message: fmt.Sprintf("field %s must be one of: %s", field.Name, strings.Join(field.Type.Values, ", "))}
+}
+
+func invalidFormat(field *TableField, format string) *FieldError {
+	return &FieldError{Field: field.Name, Code: CodeInvalidFormat, Format: format,
+		message: fmt.Sprintf("field %s expects format %s", field.Name, format)}
+}
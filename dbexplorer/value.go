@@ -0,0 +1,325 @@
+package dbexplorer
+
+import (
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const uuidBinaryLen = 16
+
+// parseFieldValue converts path or query string value into query arg by column type
+func parseFieldValue(field *TableField, value string) (interface{}, error) {
+	switch field.Type.Kind {
+	case KindInt, KindYear:
+		val, err := parseInt(field.Type, value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s expects number", field.Name)
+		}
+		return val, nil
+
+	case KindDecimal, KindFloat:
+		if _, err := strconv.ParseFloat(value, 64); err != nil {
+			return nil, fmt.Errorf("field %s expects number", field.Name)
+		}
+		return value, nil
+
+	case KindBool, KindBit:
+		if b, err := strconv.ParseBool(value); err == nil {
+			return boolToInt(b), nil
+		}
+		val, err := strconv.ParseUint(value, 10, 64)
+		if err != nil {
+			return nil, fmt.Errorf("field %s expects true or false", field.Name)
+		}
+		return val, nil
+
+	case KindBinary:
+		val, err := parseBinary(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s expects hex or uuid", field.Name)
+		}
+		return val, nil
+
+	case KindBlob:
+		val, err := base64.StdEncoding.DecodeString(value)
+		if err != nil {
+			return nil, fmt.Errorf("field %s expects base64", field.Name)
+		}
+		return val, nil
+	}
+
+	return value, nil
+}
+
+// isValidValue checks json body value suits column type
+func isValidValue(t *ColumnType, val interface{}) bool {
+	switch t.Kind {
+	case KindInt, KindYear:
+		num, ok := numberString(val)
+		if !ok && t.Kind == KindYear {
+			num, ok = val.(string)
+		}
+		if !ok {
+			return false
+		}
+		_, err := parseInt(t, num)
+		return err == nil
+
+	case KindDecimal, KindFloat:
+		num, ok := numberString(val)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseFloat(num, 64)
+		return err == nil
+
+	case KindBool, KindBit:
+		if _, ok := val.(bool); ok {
+			return true
+		}
+		num, ok := numberString(val)
+		if !ok {
+			return false
+		}
+		_, err := strconv.ParseUint(num, 10, 64)
+		return err == nil
+
+	case KindJSON:
+		return true
+
+	case KindSet:
+		if list, ok := val.([]interface{}); ok {
+			for _, item := range list {
+				if _, ok := item.(string); !ok {
+					return false
+				}
+			}
+			return true
+		}
+		_, ok := val.(string)
+		return ok
+
+	case KindBinary:
+		str, ok := val.(string)
+		if !ok {
+			return false
+		}
+		_, err := parseBinary(str)
+		return err == nil
+
+	case KindBlob:
+		str, ok := val.(string)
+		if !ok {
+			return false
+		}
+		_, err := base64.StdEncoding.DecodeString(str)
+		return err == nil
+	}
+
+	_, ok := val.(string)
+	return ok
+}
+
+// writeFieldValue converts valid json body value into query arg by column type
+func writeFieldValue(field *TableField, val interface{}) interface{} {
+	if val == nil {
+		return nil
+	}
+
+	switch field.Type.Kind {
+	case KindInt, KindYear:
+		if num, ok := numberString(val); ok {
+			if v, err := parseInt(field.Type, num); err == nil {
+				return v
+			}
+		}
+
+	case KindDecimal:
+		if num, ok := numberString(val); ok {
+			return num
+		}
+
+	case KindFloat:
+		if num, ok := numberString(val); ok {
+			if v, err := strconv.ParseFloat(num, 64); err == nil {
+				return v
+			}
+		}
+
+	case KindBool, KindBit:
+		if b, ok := val.(bool); ok {
+			return boolToInt(b)
+		}
+		if num, ok := numberString(val); ok {
+			if v, err := strconv.ParseUint(num, 10, 64); err == nil {
+				return v
+			}
+		}
+
+	case KindJSON:
+		if data, err := json.Marshal(val); err == nil {
+			return string(data)
+		}
+
+	case KindSet:
+		if list, ok := val.([]interface{}); ok {
+			members := make([]string, 0, len(list))
+			for _, item := range list {
+				members = append(members, fmt.Sprint(item))
+			}
+			return strings.Join(members, ",")
+		}
+
+	case KindBinary:
+		if str, ok := val.(string); ok {
+			if b, err := parseBinary(str); err == nil {
+				return b
+			}
+		}
+
+	case KindBlob:
+		if str, ok := val.(string); ok {
+			if b, err := base64.StdEncoding.DecodeString(str); err == nil {
+				return b
+			}
+		}
+	}
+
+	return val
+}
+
+// readFieldValue converts scanned column value into typed json value
+func readFieldValue(field *TableField, value string) interface{} {
+	switch field.Type.Kind {
+	case KindInt, KindYear:
+		if val, err := parseInt(field.Type, value); err == nil {
+			return val
+		}
+
+	case KindDecimal:
+		return json.Number(value)
+
+	case KindFloat:
+		if val, err := strconv.ParseFloat(value, 64); err == nil {
+			return val
+		}
+
+	case KindBool:
+		return value != "0" && value != "false"
+
+	case KindDate, KindDateTime, KindTime:
+		return readTime(field.Type.Kind, value)
+
+	case KindBit:
+		bits := readBits(value)
+		if field.Type.Length == 1 {
+			return bits == 1
+		}
+		return bits
+
+	case KindJSON:
+		if json.Valid([]byte(value)) {
+			return json.RawMessage(value)
+		}
+
+	case KindSet:
+		if value == "" {
+			return []string{}
+		}
+		return strings.Split(value, ",")
+
+	case KindBinary:
+		return formatBinary([]byte(value))
+
+	case KindBlob:
+		// encoding/json writes bytes as base64
+		return []byte(value)
+	}
+
+	return value
+}
+
+// readTime formats time of drivers scanning dates into time.Time,
+// mysql driver gives them as is
+func readTime(kind TypeKind, value string) string {
+	t, err := time.Parse(time.RFC3339Nano, value)
+	if err != nil {
+		return value
+	}
+
+	switch kind {
+	case KindDate:
+		return t.Format("2006-01-02")
+	case KindTime:
+		return t.Format("15:04:05.999999")
+	}
+
+	return t.Format("2006-01-02 15:04:05.999999")
+}
+
+func parseInt(t *ColumnType, value string) (interface{}, error) {
+	if t.Unsigned {
+		return strconv.ParseUint(value, 10, 64)
+	}
+
+	return strconv.ParseInt(value, 10, 64)
+}
+
+// numberString gives number from json body as string, body is decoded with UseNumber
+func numberString(val interface{}) (string, bool) {
+	switch v := val.(type) {
+	case json.Number:
+		return v.String(), true
+	case float64:
+		return strconv.FormatFloat(v, 'f', -1, 64), true
+	case int, int64, uint64:
+		return fmt.Sprint(v), true
+	}
+
+	return "", false
+}
+
+func boolToInt(b bool) int64 {
+	if b {
+		return 1
+	}
+
+	return 0
+}
+
+// readBits converts BIT(n) value which driver gives as big endian bytes
+func readBits(value string) uint64 {
+	buf := make([]byte, 8)
+	b := []byte(value)
+	if len(b) > len(buf) {
+		b = b[len(b)-len(buf):]
+	}
+	copy(buf[len(buf)-len(b):], b)
+
+	return binary.BigEndian.Uint64(buf)
+}
+
+// parseBinary accepts hex string or uuid like 123e4567-e89b-12d3-a456-426614174000
+func parseBinary(value string) ([]byte, error) {
+	if len(value) == 36 && strings.Count(value, "-") == 4 {
+		value = strings.ReplaceAll(value, "-", "")
+	}
+
+	return hex.DecodeString(value)
+}
+
+// formatBinary renders 16 bytes as uuid and anything else as hex
+func formatBinary(b []byte) string {
+	h := hex.EncodeToString(b)
+	if len(b) != uuidBinaryLen {
+		return h
+	}
+
+	return h[0:8] + "-" + h[8:12] + "-" + h[12:16] + "-" + h[16:20] + "-" + h[20:32]
+}
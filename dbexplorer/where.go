@@ -0,0 +1,122 @@
+package dbexplorer
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+)
+
+// WhereQuery selects records for mass update or delete. Empty filter is refused
+// unless All is set, MaxAffected > 0 refuses to touch more records than that
+type WhereQuery struct {
+	Filters     []*Filter
+	All         bool
+	MaxAffected int
+	DryRun      bool
+}
+
+// WriteResult is number of records matched by filter and changed by query,
+// dry run changes nothing
+type WriteResult struct {
+	Matched  int
+	Affected int
+}
+
+func (exp *Explorer) UpdateRecords(ctx context.Context, table string, query *WhereQuery, data map[string]interface{}) (*WriteResult, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return nil, ErrTableNotFound
+	}
+
+	if err := exp.ValidateUpdateData(table, data); err != nil {
+		return nil, err
+	}
+
+	if len(data) == 0 {
+		return nil, fmt.Errorf("%w: nothing to update", ErrInvalidQuery)
+	}
+
+	values := []interface{}{}
+	fields := []*TableField{}
+	for _, field := range exp.getTableFields(table) {
+		val, ex := data[field.Name]
+		if !ex {
+			continue
+		}
+
+		values = append(values, writeFieldValue(field, val))
+		fields = append(fields, field)
+	}
+
+	return exp.writeWhere(ctx, table, query, func(q querier, where string, args []interface{}) (sql.Result, error) {
+		sqlQuery := exp.builder().update(table, fields, where)
+		return q.ExecContext(ctx, sqlQuery, append(values, args...)...)
+	})
+}
+
+func (exp *Explorer) DeleteRecords(ctx context.Context, table string, query *WhereQuery) (*WriteResult, error) {
+	exp = exp.snapshot()
+	ctx, cancel := exp.withTimeout(ctx, opWrite)
+	defer cancel()
+
+	if !exp.HasTable(table) {
+		return nil, ErrTableNotFound
+	}
+
+	return exp.writeWhere(ctx, table, query, func(q querier, where string, args []interface{}) (sql.Result, error) {
+		return q.ExecContext(ctx, exp.builder().deleteFrom(table, where), args...)
+	})
+}
+
+// writeWhere counts and locks matched records, checks guards and runs write in the same transaction
+func (exp *Explorer) writeWhere(ctx context.Context, table string, query *WhereQuery, write func(q querier, where string, args []interface{}) (sql.Result, error)) (*WriteResult, error) {
+	if len(exp.getKeyFields(table)) == 0 {
+		return nil, ErrReadOnlyTable
+	}
+
+	if len(query.Filters) == 0 && !query.All {
+		return nil, fmt.Errorf("%w: empty filter, pass all=true to affect every record", ErrInvalidQuery)
+	}
+
+	where, args, err := exp.buildWhere(table, query.Filters)
+	if err != nil {
+		return nil, err
+	}
+
+	tx, err := exp.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer tx.Rollback()
+
+	q := exp.bind(tx)
+	result := &WriteResult{}
+	err = q.QueryRowContext(ctx, exp.dialect.lockedCount(exp.quote(table)+where), args...).Scan(&result.Matched)
+	if err != nil {
+		return nil, err
+	}
+
+	if query.MaxAffected > 0 && result.Matched > query.MaxAffected {
+		return nil, fmt.Errorf("%w: %d records match, max %d", ErrTooManyRecords, result.Matched, query.MaxAffected)
+	}
+
+	if query.DryRun {
+		return result, nil
+	}
+
+	res, err := write(q, where, args)
+	if err != nil {
+		return nil, err
+	}
+
+	affected, err := res.RowsAffected()
+	if err != nil {
+		return nil, err
+	}
+	result.Affected = int(affected)
+
+	return result, tx.Commit()
+}
@@ -0,0 +1,141 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"db_explorer/dbexplorer"
+	"errors"
+	"path/filepath"
+	"strings"
+	"testing"
+	"unicode/utf8"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// FuzzQuoteIdent проверяет, что из кавычек нельзя выйти: имя читается обратно без изменений,
+// а sqlite получает колонку ровно с таким именем
+func FuzzQuoteIdent(f *testing.F) {
+	for _, seed := range []string{"id", "order", "unit price", "due-date", "a`b", `a"b`, "x; DROP TABLE items; --", ""} {
+		f.Add(seed)
+	}
+
+	db, err := sql.Open("sqlite3", "file:"+filepath.Join(f.TempDir(), "quote.db"))
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer db.Close()
+
+	dialects := map[string]dbexplorer.Dialect{
+		"`": dbexplorer.MySQL(),
+		`"`: dbexplorer.Postgres(),
+	}
+
+	f.Fuzz(func(t *testing.T, name string) {
+		for q, d := range dialects {
+			quoted := d.QuoteIdent(name)
+			if len(quoted) < 2 || !strings.HasPrefix(quoted, q) || !strings.HasSuffix(quoted, q) {
+				t.Fatalf("%s: %q is not quoted: %s", d.Name(), name, quoted)
+			}
+
+			inner := quoted[1 : len(quoted)-1]
+			if strings.Contains(strings.ReplaceAll(inner, q+q, ""), q) {
+				t.Fatalf("%s: %q breaks out of quotes: %s", d.Name(), name, quoted)
+			}
+			if strings.ReplaceAll(inner, q+q, q) != name {
+				t.Fatalf("%s: %q is changed by quoting: %s", d.Name(), name, quoted)
+			}
+		}
+
+		// sqlite does not accept empty names and zero bytes
+		if name == "" || strings.ContainsRune(name, 0) || !utf8.ValidString(name) {
+			return
+		}
+
+		rows, err := db.Query("SELECT 1 AS " + dbexplorer.SQLite().QuoteIdent(name))
+		if err != nil {
+			t.Fatalf("%q: %s", name, err)
+		}
+		defer rows.Close()
+
+		cols, _ := rows.Columns()
+		if len(cols) != 1 || cols[0] != name {
+			t.Fatalf("%q: got columns %q", name, cols)
+		}
+	})
+}
+
+// FuzzRecordsQuery передаёт произвольные таблицы, поля, фильтры и сортировку из запроса:
+// explorer должен либо выполнить запрос, либо отказать сам, но не отдать в базу битый sql
+func FuzzRecordsQuery(f *testing.F) {
+	f.Add("order", "unit price__gte", "20", "-due-date", "id,unit price")
+	f.Add("items", "title__in", "a,b", "id", "id); DROP TABLE items; --")
+	f.Add("notes", "body__like", "%'--", "-body`", "body")
+	f.Add("order\" --", "id", "1", "id", "")
+	f.Add("items", "id = 1 OR 1", "1", "", "*")
+
+	dsn := "file:" + filepath.Join(f.TempDir(), "fuzz.db") + "?_foreign_keys=on&_busy_timeout=5000"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		f.Fatal(err)
+	}
+	defer db.Close()
+
+	PrepareSQLiteApis(db)
+	explorer := dbexplorer.NewSqlExplorer(db, dbexplorer.WithDialect(dbexplorer.SQLite()))
+
+	f.Fuzz(func(t *testing.T, table string, key string, value string, sort string, fields string) {
+		ctx := context.Background()
+		query := &dbexplorer.RecordsQuery{
+			Filters: []*dbexplorer.Filter{dbexplorer.ParseFilter(key, value)},
+			Sort:    dbexplorer.ParseSort(sort),
+			Fields:  dbexplorer.ParseFields(fields),
+			Limit:   5,
+		}
+
+		_, err := explorer.GetRecords(ctx, table, query)
+		checkRejected(t, "get records", err)
+
+		_, err = explorer.GetRecord(ctx, table, dbexplorer.RecordID{value}, query.Fields)
+		checkRejected(t, "get record", err)
+
+		_, err = explorer.UpdateRecords(ctx, table, &dbexplorer.WhereQuery{Filters: query.Filters, DryRun: true}, map[string]interface{}{key: value})
+		checkRejected(t, "update records", err)
+
+		_, err = explorer.CreateRecord(ctx, table, map[string]interface{}{key: value})
+		checkRejected(t, "create record", err)
+
+		tables, _ := explorer.GetTables()
+		if len(tables) != 5 {
+			t.Fatalf("tables are changed: %v", tables)
+		}
+	})
+}
+
+// checkRejected passes errors explorer returns itself, any other one came from database
+func checkRejected(t *testing.T, op string, err error) {
+	t.Helper()
+
+	if err == nil {
+		return
+	}
+
+	var verr *dbexplorer.ValidationError
+	known := []error{
+		dbexplorer.ErrTableNotFound,
+		dbexplorer.ErrRecordNotFound,
+		dbexplorer.ErrInvalidQuery,
+		dbexplorer.ErrInvalidID,
+		dbexplorer.ErrReadOnlyTable,
+	}
+	for _, kerr := range known {
+		if errors.Is(err, kerr) {
+			return
+		}
+	}
+	if errors.As(err, &verr) {
+		return
+	}
+
+	t.Fatalf("%s: database error: %s", op, err)
+}
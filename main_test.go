@@ -66,6 +66,55 @@ func PrepareTestApis(db *sql.DB) {
 
 		`INSERT INTO users (user_id, login, password, email, info, updated) VALUES
 (1,	'rvasily',	'love',	'rvasily@example.com',	'none',	NULL);`,
+
+		`DROP TABLE IF EXISTS items_users;`,
+
+		`CREATE TABLE items_users (
+  item_id int(11) NOT NULL,
+  user_id int(11) NOT NULL,
+  PRIMARY KEY (item_id, user_id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`,
+
+		`DROP TABLE IF EXISTS sessions;`,
+
+		`CREATE TABLE sessions (
+  id binary(16) NOT NULL,
+  login varchar(255) NOT NULL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`,
+
+		`INSERT INTO sessions (id, login) VALUES
+(UNHEX('123e4567e89b12d3a456426614174000'),	'rvasily');`,
+
+		`DROP TABLE IF EXISTS logs;`,
+
+		`CREATE TABLE logs (
+  message varchar(255) NOT NULL,
+  level varchar(16) NOT NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`,
+
+		`INSERT INTO logs (message, level) VALUES
+('started',	'info');`,
+
+		`DROP TABLE IF EXISTS products;`,
+
+		`CREATE TABLE products (
+  id int(11) NOT NULL AUTO_INCREMENT,
+  price decimal(10,2) NOT NULL,
+  weight double DEFAULT NULL,
+  in_stock tinyint(1) NOT NULL,
+  flags bit(8) NOT NULL,
+  status enum('new','sold') NOT NULL,
+  tags set('red','green','blue') NOT NULL,
+  attrs json DEFAULT NULL,
+  released date DEFAULT NULL,
+  discount int(11) NOT NULL DEFAULT 0,
+  total decimal(10,2) AS (price - discount) VIRTUAL,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`,
+
+		`INSERT INTO products (id, price, weight, in_stock, flags, status, tags, attrs, released) VALUES
+(1,	19.99,	1.5,	1,	b'00000101',	'new',	'red,blue',	'{"size": "xl"}',	'2023-01-02');`,
 	}
 
 	for _, q := range qs {
@@ -78,8 +127,14 @@ func PrepareTestApis(db *sql.DB) {
 
 func CleanupTestApis(db *sql.DB) {
 	qs := []string{
+		`DROP TABLE IF EXISTS notes;`,
+		`DROP TABLE IF EXISTS accounts;`,
 		`DROP TABLE IF EXISTS items;`,
 		`DROP TABLE IF EXISTS users;`,
+		`DROP TABLE IF EXISTS items_users;`,
+		`DROP TABLE IF EXISTS sessions;`,
+		`DROP TABLE IF EXISTS logs;`,
+		`DROP TABLE IF EXISTS products;`,
 	}
 	for _, q := range qs {
 		_, err := db.Exec(q)
@@ -90,6 +145,14 @@ func CleanupTestApis(db *sql.DB) {
 }
 
 func TestApis(t *testing.T) {
+	// skip must be asked explicitly, missing .env fails instead of hiding the suite
+	if os.Getenv("SKIP_MYSQL_TESTS") != "" {
+		t.Skip("SKIP_MYSQL_TESTS is set")
+	}
+	if _, err := os.Stat("./.env"); err != nil {
+		t.Fatal("no .env for mysql tests, run docker compose or set SKIP_MYSQL_TESTS=1 to run sqlite ones only")
+	}
+
 	loadEnv()
 	// DSN = "user:pass@tcp(localhost:3306)/dbname?charset=utf8"
 	dsn := fmt.Sprintf("%s:%s@tcp(%s:%s)/%s?charset=utf8",
@@ -122,7 +185,7 @@ func TestApis(t *testing.T) {
 			Path: "/", // список таблиц
 			Result: CR{
 				"response": CR{
-					"tables": []string{"items", "users"},
+					"tables": []string{"items", "items_users", "logs", "products", "sessions", "users"},
 				},
 			},
 		},
@@ -133,6 +196,61 @@ func TestApis(t *testing.T) {
 				"error": "unknown table",
 			},
 		},
+		Case{
+			Path:   "/unknown_table/_schema",
+			Status: http.StatusNotFound,
+			Result: CR{
+				"error": "unknown table",
+			},
+		},
+		Case{
+			Path: "/items_users/_schema",
+			Result: CR{
+				"response": CR{
+					"table": CR{
+						"name": "items_users",
+						"fields": []CR{
+							CR{
+								"name":           "item_id",
+								"type":           CR{"name": "int", "kind": "int", "unsigned": false},
+								"nullable":       false,
+								"primary":        true,
+								"auto_increment": false,
+								"generated":      false,
+								"on_update":      false,
+								"default":        nil,
+								"comment":        "",
+								"required":       true,
+							},
+							CR{
+								"name":           "user_id",
+								"type":           CR{"name": "int", "kind": "int", "unsigned": false},
+								"nullable":       false,
+								"primary":        true,
+								"auto_increment": false,
+								"generated":      false,
+								"on_update":      false,
+								"default":        nil,
+								"comment":        "",
+								"required":       true,
+							},
+						},
+						"record_key": []string{"item_id", "user_id"},
+						"read_only":  false,
+						"indexes": []CR{
+							CR{
+								"name":       "PRIMARY",
+								"unique":     true,
+								"type":       "BTREE",
+								"fields":     []string{"item_id", "user_id"},
+								"expression": false,
+							},
+						},
+						"foreign_keys": []CR{},
+					},
+				},
+			},
+		},
 		Case{
 			Path: "/items",
 			Result: CR{
@@ -317,46 +435,58 @@ func TestApis(t *testing.T) {
 		Case{
 			Path:   "/items/3",
 			Method: http.MethodPost,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"id": 4, // primary key нельзя обновлять у существующей записи
 			},
 			Result: CR{
-				"error": "field id have invalid type",
+				"error": "field id is read-only",
+				"errors": []CR{
+					CR{"field": "id", "code": "read_only"},
+				},
 			},
 		},
 		Case{
 			Path:   "/items/3",
 			Method: http.MethodPost,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"title": 42,
 			},
 			Result: CR{
 				"error": "field title have invalid type",
+				"errors": []CR{
+					CR{"field": "title", "code": "invalid_type"},
+				},
 			},
 		},
 		Case{
 			Path:   "/items/3",
 			Method: http.MethodPost,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"title": nil,
 			},
 			Result: CR{
 				"error": "field title have invalid type",
+				"errors": []CR{
+					CR{"field": "title", "code": "invalid_type"},
+				},
 			},
 		},
 
 		Case{
 			Path:   "/items/3",
 			Method: http.MethodPost,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"updated": 42,
 			},
 			Result: CR{
 				"error": "field updated have invalid type",
+				"errors": []CR{
+					CR{"field": "updated", "code": "invalid_type"},
+				},
 			},
 		},
 
@@ -436,18 +566,21 @@ func TestApis(t *testing.T) {
 		Case{
 			Path:   "/users/1",
 			Method: http.MethodPost,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"user_id": 1, // primary key нельзя обновлять у существующей записи
 			},
 			Result: CR{
-				"error": "field user_id have invalid type",
+				"error": "field user_id is read-only",
+				"errors": []CR{
+					CR{"field": "user_id", "code": "read_only"},
+				},
 			},
 		},
 		Case{
 			Path:   "/users/",
 			Method: http.MethodPut,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"user_id":    2,
 				"login":      "qwerty'",
@@ -455,13 +588,18 @@ func TestApis(t *testing.T) {
 				"unkn_field": "love",
 			},
 			Result: CR{
-				"error": "need required field email",
+				"error": "need required field email; need required field info; undefined field: unkn_field",
+				"errors": []CR{
+					CR{"field": "email", "code": "required"},
+					CR{"field": "info", "code": "required"},
+					CR{"field": "unkn_field", "code": "unknown_field"},
+				},
 			},
 		},
 		Case{
 			Path:   "/users/",
 			Method: http.MethodPut,
-			Status: http.StatusBadRequest,
+			Status: http.StatusUnprocessableEntity,
 			Body: CR{
 				"user_id":    2,
 				"email":      "",
@@ -472,6 +610,9 @@ func TestApis(t *testing.T) {
 			},
 			Result: CR{
 				"error": "undefined field: unkn_field",
+				"errors": []CR{
+					CR{"field": "unkn_field", "code": "unknown_field"},
+				},
 			},
 		},
 		Case{
@@ -531,9 +672,1266 @@ func TestApis(t *testing.T) {
 				},
 			},
 		},
-	}
 
-	runCases(t, ts, db, cases)
+		// фильтрация
+		Case{
+			Path:  "/items",
+			Query: "title=memcache",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":          2,
+							"title":       "memcache",
+							"description": "Рассказать про мемкеш с примером использования",
+							"updated":     nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "updated__isnull=false",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":          1,
+							"title":       "database/sql",
+							"description": "Рассказать про базы данных",
+							"updated":     "rvasily",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/users",
+			Query: "user_id__gt=1&login__in=rvasily,qwerty'",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id":  2,
+							"login":    "qwerty'",
+							"password": "love\"",
+							"email":    "",
+							"info":     "",
+							"updated":  nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/users",
+			Query: "email__like=%25@example.com",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id":  1,
+							"login":    "rvasily",
+							"password": "love",
+							"email":    "rvasily@example.com",
+							"info":     "try update",
+							"updated":  "now",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/users",
+			Query:  "unkn_field=1",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: unknown field unkn_field",
+			},
+		},
+		Case{
+			Path:   "/users",
+			Query:  "user_id__gt=one",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: field user_id expects number",
+			},
+		},
+
+		// сортировка
+		Case{
+			Path:  "/users",
+			Query: "sort=-user_id&limit=1",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id":  2,
+							"login":    "qwerty'",
+							"password": "love\"",
+							"email":    "",
+							"info":     "",
+							"updated":  nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "sort=-updated,title&limit=1",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":          1,
+							"title":       "database/sql",
+							"description": "Рассказать про базы данных",
+							"updated":     "rvasily",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items",
+			Query:  "sort=unkn_field",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: unknown sort field unkn_field",
+			},
+		},
+
+		// keyset-пагинация
+		Case{
+			Path:  "/users",
+			Query: "limit=1&cursor=",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id":  1,
+							"login":    "rvasily",
+							"password": "love",
+							"email":    "rvasily@example.com",
+							"info":     "try update",
+							"updated":  "now",
+						},
+					},
+					"next_cursor": "eyJmIjpbInVzZXJfaWQiXSwidiI6WzFdfQ",
+				},
+			},
+		},
+		Case{
+			Path:  "/users",
+			Query: "limit=5&cursor=eyJmIjpbInVzZXJfaWQiXSwidiI6WzFdfQ",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id":  2,
+							"login":    "qwerty'",
+							"password": "love\"",
+							"email":    "",
+							"info":     "",
+							"updated":  nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/users",
+			Query:  "sort=login&cursor=eyJmIjpbInVzZXJfaWQiXSwidiI6WzFdfQ",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: cursor does not match sort",
+			},
+		},
+
+		// выбор полей
+		Case{
+			Path:  "/items",
+			Query: "fields=id,title",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":    1,
+							"title": "database/sql",
+						},
+						CR{
+							"id":    2,
+							"title": "memcache",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/users",
+			Query: "fields=login&limit=1&cursor=",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"login": "rvasily",
+						},
+					},
+					"next_cursor": "eyJmIjpbInVzZXJfaWQiXSwidiI6WzFdfQ",
+				},
+			},
+		},
+		Case{
+			Path:  "/users/2",
+			Query: "fields=login,email",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"login": "qwerty'",
+						"email": "",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/users/2",
+			Query:  "fields=login,unkn_field",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: unknown field unkn_field",
+			},
+		},
+
+		// количество записей
+		Case{
+			Path:  "/items",
+			Query: "count=exact&limit=1&offset=1&fields=id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id": 2,
+						},
+					},
+					"total":  2,
+					"limit":  1,
+					"offset": 1,
+				},
+			},
+		},
+		Case{
+			Path:  "/users",
+			Query: "count=exact&login=rvasily&fields=user_id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"user_id": 1,
+						},
+					},
+					"total":  1,
+					"limit":  5,
+					"offset": 0,
+				},
+			},
+		},
+		Case{
+			Path:   "/users",
+			Query:  "count=maybe",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: count expects exact or estimated",
+			},
+		},
+
+		// составной первичный ключ
+		Case{
+			Path:   "/items_users/",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"user_id": 2,
+			},
+			Result: CR{
+				"response": CR{
+					"id": []int{1, 2},
+				},
+			},
+		},
+		Case{
+			Path: "/items_users/1,2",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"item_id": 1,
+						"user_id": 2,
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/1",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid param: id: expect 2 key values",
+			},
+		},
+		Case{
+			Path:   "/items_users/1,2",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"deleted": 1,
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/1,2",
+			Status: http.StatusNotFound,
+			Result: CR{
+				"error": "record not found",
+			},
+		},
+
+		// нецелочисленные первичные ключи
+		Case{
+			Path: "/sessions/123e4567-e89b-12d3-a456-426614174000",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":    "123e4567-e89b-12d3-a456-426614174000",
+						"login": "rvasily",
+					},
+				},
+			},
+		},
+		Case{
+			Path: "/sessions/123e4567e89b12d3a456426614174000",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":    "123e4567-e89b-12d3-a456-426614174000",
+						"login": "rvasily",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/sessions/",
+			Method: http.MethodPut,
+			Body: CR{
+				"id":    "00000000-0000-0000-0000-000000000042",
+				"login": "qwerty'",
+			},
+			Result: CR{
+				"response": CR{
+					"id": "00000000-0000-0000-0000-000000000042",
+				},
+			},
+		},
+		Case{
+			Path:   "/sessions/not-a-uuid",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid param: id: field id expects hex or uuid",
+			},
+		},
+		Case{
+			Path:   "/users/abc",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid param: id: field user_id expects number",
+			},
+		},
+
+		// таблица без первичного ключа доступна только на чтение
+		Case{
+			Path: "/logs",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"message": "started",
+							"level":   "info",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/logs/1",
+			Status: http.StatusMethodNotAllowed,
+			Result: CR{
+				"error": "table has no primary key, records are read-only",
+			},
+		},
+		Case{
+			Path:   "/logs/",
+			Method: http.MethodPut,
+			Status: http.StatusMethodNotAllowed,
+			Body: CR{
+				"message": "stopped",
+				"level":   "info",
+			},
+			Result: CR{
+				"error": "table has no primary key, records are read-only",
+			},
+		},
+		Case{
+			Path:   "/logs/1",
+			Method: http.MethodDelete,
+			Status: http.StatusMethodNotAllowed,
+			Result: CR{
+				"error": "table has no primary key, records are read-only",
+			},
+		},
+
+		// типы колонок
+		Case{
+			Path: "/products/1",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":       1,
+						"price":    19.99,
+						"weight":   1.5,
+						"in_stock": true,
+						"flags":    5,
+						"status":   "new",
+						"tags":     []string{"red", "blue"},
+						"attrs":    CR{"size": "xl"},
+						"released": "2023-01-02",
+						"discount": 0,
+						"total":    19.99,
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/products/",
+			Method: http.MethodPut,
+			Body: CR{
+				"price":    10.5,
+				"weight":   nil,
+				"in_stock": false,
+				"flags":    3,
+				"status":   "sold",
+				"tags":     []string{"green"},
+				"attrs":    CR{"a": []int{1, 2}},
+				"released": "2024-05-06",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 2,
+				},
+			},
+		},
+		Case{
+			Path: "/products/2",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":       2,
+						"price":    10.5,
+						"weight":   nil,
+						"in_stock": false,
+						"flags":    3,
+						"status":   "sold",
+						"tags":     []string{"green"},
+						"attrs":    CR{"a": []int{1, 2}},
+						"released": "2024-05-06",
+						"discount": 0,
+						"total":    10.5,
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/products/2",
+			Method: http.MethodPost,
+			Status: http.StatusUnprocessableEntity,
+			Body: CR{
+				"in_stock": "yes",
+			},
+			Result: CR{
+				"error": "field in_stock have invalid type",
+				"errors": []CR{
+					CR{"field": "in_stock", "code": "invalid_type"},
+				},
+			},
+		},
+
+		// валидация по ограничениям колонок
+		Case{
+			Path:   "/products/2",
+			Method: http.MethodPost,
+			Status: http.StatusUnprocessableEntity,
+			Body: CR{
+				"price":    123456789.5,
+				"flags":    300,
+				"status":   "lost",
+				"released": "06.05.2024",
+			},
+			Result: CR{
+				"error": "field price has too many digits, decimal(10,2); field flags is out of range, max 255; field status must be one of: new, sold; field released expects format YYYY-MM-DD",
+				"errors": []CR{
+					CR{"field": "price", "code": "too_many_digits", "precision": 10, "scale": 2},
+					CR{"field": "flags", "code": "out_of_range", "min": 0, "max": 255},
+					CR{"field": "status", "code": "invalid_choice", "allowed": []string{"new", "sold"}},
+					CR{"field": "released", "code": "invalid_format", "format": "YYYY-MM-DD"},
+				},
+			},
+		},
+
+		// значения по умолчанию и генерируемые колонки
+		Case{
+			Path:   "/products/",
+			Method: http.MethodPut,
+			Status: http.StatusUnprocessableEntity,
+			Body: CR{
+				"price":    1,
+				"in_stock": true,
+				"flags":    0,
+				"status":   "new",
+				"tags":     "",
+				"total":    1,
+			},
+			Result: CR{
+				"error": "field total is generated and can not be written",
+				"errors": []CR{
+					CR{"field": "total", "code": "generated"},
+				},
+			},
+		},
+		Case{
+			Path:   "/products/",
+			Method: http.MethodPut,
+			Body: CR{
+				"price":    1,
+				"in_stock": true,
+				"flags":    0,
+				"status":   "new",
+				"tags":     "",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 3,
+				},
+			},
+		},
+		Case{
+			Path:  "/products/3",
+			Query: "fields=weight,attrs,discount,total",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"weight":   nil,
+						"attrs":    nil,
+						"discount": 0,
+						"total":    1,
+					},
+				},
+			},
+		},
+	}
+
+	runCases(t, ts, db, cases)
+
+	// таблица появилась после старта, до перезагрузки схемы её нет
+	_, err = db.Exec(`CREATE TABLE notes (
+  id int NOT NULL AUTO_INCREMENT,
+  item_id int(11) DEFAULT NULL,
+  body text NOT NULL,
+  PRIMARY KEY (id),
+  CONSTRAINT notes_item FOREIGN KEY (item_id) REFERENCES items (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`)
+	if err != nil {
+		panic(err)
+	}
+
+	runCases(t, ts, db, []Case{
+		Case{
+			Path:   "/notes",
+			Status: http.StatusNotFound,
+			Result: CR{
+				"error": "unknown table",
+			},
+		},
+		Case{
+			Path:   "/_schema/reload",
+			Method: http.MethodPost,
+			Result: CR{
+				"response": CR{
+					"tables": []string{"items", "items_users", "logs", "notes", "products", "sessions", "users"},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"body": "schema reloaded",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 1,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"body":    "about sql",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 2,
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "expand=item",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":      1,
+							"item_id": nil,
+							"body":    "schema reloaded",
+							"item":    nil,
+						},
+						CR{
+							"id":      2,
+							"item_id": 1,
+							"body":    "about sql",
+							"item": CR{
+								"id":          1,
+								"title":       "database/sql",
+								"description": "Рассказать про базы данных",
+								"updated":     "rvasily",
+							},
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/2",
+			Query:  "fields=id&expand=item",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: expand needs field item_id",
+			},
+		},
+		Case{
+			Path:   "/notes",
+			Query:  "expand=author",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: unknown relation author",
+			},
+		},
+		Case{
+			Path:  "/items/1/notes",
+			Query: "fields=id,body",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":   2,
+							"body": "about sql",
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path: "/items/2/notes",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "q=sql&fields=id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":     2,
+							"_score": 1,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "q=%25",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes/_aggregate",
+			Query: "group_by=item_id&metrics=count,max:id,sum:id",
+			Result: CR{
+				"response": CR{
+					"groups": []CR{
+						CR{
+							"item_id": nil,
+							"count":   1,
+							"max_id":  1,
+							"sum_id":  1,
+						},
+						CR{
+							"item_id": 1,
+							"count":   1,
+							"max_id":  2,
+							"sum_id":  2,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes/_aggregate",
+			Query: "id__gt=1",
+			Result: CR{
+				"response": CR{
+					"groups": []CR{
+						CR{
+							"count": 1,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_aggregate",
+			Query:  "metrics=sum:body",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: sum expects numeric field body",
+			},
+		},
+		Case{
+			Path:   "/items/1/users",
+			Status: http.StatusNotFound,
+			Result: CR{
+				"error": "relation not found: users does not reference items",
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodPut,
+			Body: []CR{
+				CR{
+					"body": "first of many",
+				},
+				CR{
+					"item_id": 2,
+					"body":    "second of many",
+				},
+			},
+			Result: CR{
+				"response": CR{
+					"ids":    []interface{}{3, 4},
+					"errors": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_bulk",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{
+					"body": "valid",
+				},
+				CR{},
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "record 1: need required field body",
+				"rows": []CR{
+					CR{
+						"index": 1,
+						"error": "need required field body",
+						"errors": []CR{
+							CR{"field": "body", "code": "required"},
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_bulk?mode=best_effort",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{
+					"body": "valid",
+				},
+				CR{},
+			},
+			Result: CR{
+				"response": CR{
+					"ids": []interface{}{5, nil},
+					"errors": []CR{
+						CR{
+							"index": 1,
+							"error": "need required field body",
+							"errors": []CR{
+								CR{"field": "body", "code": "required"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_bulk",
+			Method: http.MethodPost,
+			Body: CR{
+				"body": "not a list",
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "body must be a list of records",
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodDelete,
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: empty filter, pass all=true to affect every record",
+			},
+		},
+		Case{
+			Path:   "/notes/?item_id__isnull=true&dry_run=true",
+			Method: http.MethodPatch,
+			Body: CR{
+				"body": "orphan",
+			},
+			Result: CR{
+				"response": CR{
+					"matched": 3,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/?item_id__isnull=true&max_affected=2",
+			Method: http.MethodPatch,
+			Body: CR{
+				"body": "orphan",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "too many records affected: 3 records match, max 2",
+			},
+		},
+		Case{
+			Path:   "/notes/?id=1",
+			Method: http.MethodPatch,
+			Body: CR{
+				"id": 10,
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "field id is read-only",
+				"errors": []CR{
+					CR{"field": "id", "code": "read_only"},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/?id__gte=3",
+			Method: http.MethodPatch,
+			Body: CR{
+				"body": "bulk",
+			},
+			Result: CR{
+				"response": CR{
+					"matched": 3,
+					"updated": 3,
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "body=bulk&fields=id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{"id": 3},
+						CR{"id": 4},
+						CR{"id": 5},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/?id__gte=3",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"matched": 3,
+					"deleted": 3,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/10",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"body":    "put",
+			},
+			Result: CR{
+				"response": CR{
+					"created": true,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/10",
+			Method: http.MethodPut,
+			Body: CR{
+				"id": 11,
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "field id is read-only; need required field body",
+				"errors": []CR{
+					CR{"field": "id", "code": "read_only"},
+					CR{"field": "body", "code": "required"},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/10",
+			Method: http.MethodPut,
+			Body: CR{
+				"body": "replaced",
+			},
+			Result: CR{
+				"response": CR{
+					"created": false,
+				},
+			},
+		},
+		Case{
+			Path: "/notes/10",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":      10,
+						"item_id": nil,
+						"body":    "replaced",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_upsert",
+			Method: http.MethodPost,
+			Body: CR{
+				"id":   10,
+				"body": "upserted",
+			},
+			Result: CR{
+				"response": CR{
+					"id":      10,
+					"created": false,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_upsert",
+			Method: http.MethodPost,
+			Body: CR{
+				"body": "no key",
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: upsert needs key fields: id",
+			},
+		},
+		Case{
+			Path:   "/notes/_upsert?key=unknown",
+			Method: http.MethodPost,
+			Body: CR{
+				"id":   10,
+				"body": "upserted",
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: unknown unique key unknown",
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "create",
+						"table": "items",
+						"ref":   "item",
+						"data": CR{
+							"title":       "batch",
+							"description": "created in batch",
+						},
+					},
+					CR{
+						"op":    "create",
+						"table": "items_users",
+						"data": CR{
+							"item_id": CR{"$ref": "item.id"},
+							"user_id": 1,
+						},
+					},
+					CR{
+						"op":    "update",
+						"table": "notes",
+						"id":    "10",
+						"data": CR{
+							"item_id": CR{"$ref": "item"},
+						},
+					},
+				},
+			},
+			Result: CR{
+				"response": CR{
+					"results": []CR{
+						CR{"op": "create", "table": "items", "id": 4},
+						CR{"op": "create", "table": "items_users", "id": []int{4, 1}},
+						CR{"op": "update", "table": "notes", "id": 10, "updated": 1},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes/10",
+			Query: "fields=item_id",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"item_id": 4,
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "create",
+						"table": "items",
+						"data": CR{
+							"title":       "rolled back",
+							"description": "never saved",
+						},
+					},
+					CR{
+						"op":    "update",
+						"table": "notes",
+						"id":    100500,
+						"data": CR{
+							"body": "missing",
+						},
+					},
+				},
+			},
+			Status: http.StatusNotFound,
+			Result: CR{
+				"error": "operation 1: record not found",
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "title=rolled%20back",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "create",
+						"table": "notes",
+						"data":  CR{},
+					},
+				},
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "operation 0: need required field body",
+				"errors": []CR{
+					CR{"field": "body", "code": "required"},
+				},
+			},
+		},
+	})
+
+	// ON DUPLICATE KEY UPDATE срабатывает на любом уникальном индексе,
+	// замена по id не должна переписать запись с тем же login
+	_, err = db.Exec(`CREATE TABLE accounts (
+  id int NOT NULL,
+  login varchar(255) NOT NULL,
+  email varchar(255) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY accounts_login (login),
+  UNIQUE KEY accounts_email (email)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8;`)
+	if err != nil {
+		panic(err)
+	}
+
+	runCases(t, ts, db, []Case{
+		Case{
+			Path:   "/_schema/reload",
+			Method: http.MethodPost,
+			Result: CR{
+				"response": CR{
+					"tables": []string{"accounts", "items", "items_users", "logs", "notes", "products", "sessions", "users"},
+				},
+			},
+		},
+		Case{
+			Path:   "/accounts/1",
+			Method: http.MethodPut,
+			Body: CR{
+				"login": "rvasily",
+				"email": "rvasily@example.com",
+			},
+			Result: CR{
+				"response": CR{
+					"created": true,
+				},
+			},
+		},
+		Case{
+			Path:   "/accounts/5",
+			Method: http.MethodPut,
+			Body: CR{
+				"login": "rvasily",
+				"email": "other@example.com",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:   "/accounts/5",
+			Method: http.MethodPut,
+			Body: CR{
+				"login": "other",
+				"email": "other@example.com",
+			},
+			Result: CR{
+				"response": CR{
+					"created": true,
+				},
+			},
+		},
+		Case{
+			Path:   "/accounts/5",
+			Method: http.MethodPut,
+			Body: CR{
+				"login": "other",
+				"email": "rvasily@example.com",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path: "/accounts/1",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":    1,
+						"login": "rvasily",
+						"email": "rvasily@example.com",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/accounts/5",
+			Method: http.MethodPut,
+			Body: CR{
+				"login": "renamed",
+				"email": "other@example.com",
+			},
+			Result: CR{
+				"response": CR{
+					"created": false,
+				},
+			},
+		},
+		Case{
+			// существующая запись сохраняет свой id
+			Path:   "/accounts/_upsert?key=accounts_login",
+			Method: http.MethodPost,
+			Body: CR{
+				"id":    7,
+				"login": "rvasily",
+				"email": "new@example.com",
+			},
+			Result: CR{
+				"response": CR{
+					"id":      1,
+					"created": false,
+				},
+			},
+		},
+		Case{
+			// email занят записью 5, mysql обновил бы её вместо новой
+			Path:   "/accounts/_upsert?key=accounts_login",
+			Method: http.MethodPost,
+			Body: CR{
+				"id":    8,
+				"login": "newbie",
+				"email": "other@example.com",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:  "/accounts",
+			Query: "fields=id,login,email",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{"id": 1, "login": "rvasily", "email": "new@example.com"},
+						CR{"id": 5, "login": "renamed", "email": "other@example.com"},
+					},
+				},
+			},
+		},
+	})
 }
 
 func runCases(t *testing.T, ts *httptest.Server, db *sql.DB, cases []Case) {
@@ -0,0 +1,940 @@
+package main
+
+import (
+	"context"
+	"database/sql"
+	"db_explorer/api"
+	"db_explorer/dbexplorer"
+	"db_explorer/pkg/router"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+	"time"
+
+	_ "github.com/mattn/go-sqlite3"
+)
+
+// PrepareSQLiteApis создаёт таблицы в файле sqlite, докер для этих тестов не нужен
+func PrepareSQLiteApis(db *sql.DB) {
+	qs := []string{
+		`CREATE TABLE items (
+  id INTEGER PRIMARY KEY,
+  title VARCHAR(255) NOT NULL,
+  description TEXT NOT NULL,
+  updated VARCHAR(255) DEFAULT NULL
+);`,
+
+		`INSERT INTO items (id, title, description, updated) VALUES
+(1,	'database/sql',	'Рассказать про базы данных',	'rvasily'),
+(2,	'memcache',	'Рассказать про мемкеш с примером использования',	NULL);`,
+
+		`CREATE TABLE items_users (
+  item_id INTEGER NOT NULL,
+  user_id INTEGER NOT NULL,
+  PRIMARY KEY (item_id, user_id)
+);`,
+
+		`CREATE TABLE notes (
+  id INTEGER PRIMARY KEY,
+  item_id INTEGER DEFAULT NULL REFERENCES items (id),
+  body TEXT NOT NULL
+);`,
+
+		`CREATE TABLE "order" (
+  "id" INTEGER PRIMARY KEY,
+  "unit price" INTEGER NOT NULL,
+  "due-date" VARCHAR(255) DEFAULT NULL
+);`,
+
+		`INSERT INTO "order" ("id", "unit price", "due-date") VALUES
+(1, 10, 'monday'),
+(2, 25, NULL);`,
+
+		`CREATE TABLE codes (
+  code TEXT PRIMARY KEY DEFAULT (lower(hex(randomblob(4)))),
+  name TEXT NOT NULL
+);`,
+
+		`CREATE UNIQUE INDEX codes_name ON codes (name);`,
+	}
+
+	for _, q := range qs {
+		_, err := db.Exec(q)
+		if err != nil {
+			panic(err)
+		}
+	}
+}
+
+func TestSQLiteApis(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "explorer.db") + "?_foreign_keys=on&_busy_timeout=5000"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	PrepareSQLiteApis(db)
+
+	explorer := dbexplorer.NewSqlExplorer(db, dbexplorer.WithDialect(dbexplorer.SQLite()))
+	expHandler := api.NewExplorerHandler(explorer)
+	handler := router.NewMuxRouter()
+	expHandler.RegisterRoutes(handler)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	cases := []Case{
+		Case{
+			Path: "/",
+			Result: CR{
+				"response": CR{
+					"tables": []string{"codes", "items", "items_users", "notes", "order"},
+				},
+			},
+		},
+		Case{
+			Path: "/notes/_schema",
+			Result: CR{
+				"response": CR{
+					"table": CR{
+						"name": "notes",
+						"fields": []CR{
+							CR{
+								"name":           "id",
+								"type":           CR{"name": "integer", "kind": "int", "unsigned": false},
+								"nullable":       false,
+								"primary":        true,
+								"auto_increment": true,
+								"generated":      false,
+								"on_update":      false,
+								"default":        nil,
+								"comment":        "",
+								"required":       false,
+							},
+							CR{
+								"name":           "item_id",
+								"type":           CR{"name": "integer", "kind": "int", "unsigned": false},
+								"nullable":       true,
+								"primary":        false,
+								"auto_increment": false,
+								"generated":      false,
+								"on_update":      false,
+								"default":        nil,
+								"comment":        "",
+								"required":       false,
+							},
+							CR{
+								"name":           "body",
+								"type":           CR{"name": "text", "kind": "string", "unsigned": false},
+								"nullable":       false,
+								"primary":        false,
+								"auto_increment": false,
+								"generated":      false,
+								"on_update":      false,
+								"default":        nil,
+								"comment":        "",
+								"required":       true,
+							},
+						},
+						"record_key": []string{"id"},
+						"read_only":  false,
+						"indexes": []CR{
+							CR{
+								"name":       "PRIMARY",
+								"unique":     true,
+								"type":       "",
+								"fields":     []string{"id"},
+								"expression": false,
+							},
+						},
+						"foreign_keys": []CR{
+							CR{
+								"name":       "notes_fk_0",
+								"fields":     []string{"item_id"},
+								"ref_table":  "items",
+								"ref_fields": []string{"id"},
+							},
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "title=memcache&fields=id,updated",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":      2,
+							"updated": nil,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/items",
+			Query: "sort=-id&limit=1&count=exact&fields=id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id": 2,
+						},
+					},
+					"total":  2,
+					"limit":  1,
+					"offset": 0,
+				},
+			},
+		},
+		Case{
+			Path:   "/items/",
+			Method: http.MethodPut,
+			Body: CR{
+				"title":       "db_crud",
+				"description": "",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 3,
+				},
+			},
+		},
+		Case{
+			Path:   "/items/3",
+			Method: http.MethodPost,
+			Body: CR{
+				"updated": "sqlite",
+			},
+			Result: CR{
+				"response": CR{
+					"updated": 1,
+				},
+			},
+		},
+		Case{
+			Path: "/items/3",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":          3,
+						"title":       "db_crud",
+						"description": "",
+						"updated":     "sqlite",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items/",
+			Method: http.MethodPut,
+			Body: CR{
+				"description": "no title",
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "need required field title",
+				"errors": []CR{
+					CR{"field": "title", "code": "required"},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"user_id": 2,
+			},
+			Result: CR{
+				"response": CR{
+					"id": []int{1, 2},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodPut,
+			Body: []CR{
+				CR{"item_id": 1, "body": "about sql"},
+				CR{"body": "no item"},
+			},
+			Result: CR{
+				"response": CR{
+					"ids":    []int{1, 2},
+					"errors": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_bulk?mode=best_effort",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{"item_id": 100500, "body": "no such item"},
+				CR{"body": "valid"},
+			},
+			Result: CR{
+				"response": CR{
+					"ids": []interface{}{nil, 3},
+					"errors": []CR{
+						CR{"index": 0, "code": "foreign_key", "error": "record refers to missing record"},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/_bulk?mode=best_effort",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{"item_id": 1, "user_id": 2},
+				CR{"item_id": 2, "user_id": 2},
+			},
+			Result: CR{
+				"response": CR{
+					"ids": []interface{}{nil, []int{2, 2}},
+					"errors": []CR{
+						CR{"index": 0, "code": "duplicate", "error": "record duplicates unique key"},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/_bulk",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{"item_id": 3, "user_id": 2},
+				CR{"item_id": 1, "user_id": 2},
+			},
+			Status: http.StatusUnprocessableEntity,
+			Result: CR{
+				"error": "record 1: record duplicates unique key",
+				"rows": []CR{
+					CR{"index": 1, "code": "duplicate", "error": "record duplicates unique key"},
+				},
+			},
+		},
+		Case{
+			Path:  "/items_users",
+			Query: "item_id=3",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"user_id": 2,
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 100500,
+				"body":    "no such item",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record refers to missing record",
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "expand=item&fields=id,item_id&limit=1",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":      1,
+							"item_id": 1,
+							"item": CR{
+								"id":          1,
+								"title":       "database/sql",
+								"description": "Рассказать про базы данных",
+								"updated":     "rvasily",
+							},
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "q=SQL&fields=id",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":     1,
+							"_score": 1,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:  "/notes",
+			Query: "q=%25",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/10",
+			Method: http.MethodPut,
+			Body: CR{
+				"item_id": 1,
+				"body":    "put",
+			},
+			Result: CR{
+				"response": CR{
+					"created": true,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/10",
+			Method: http.MethodPut,
+			Body: CR{
+				"body": "replaced",
+			},
+			Result: CR{
+				"response": CR{
+					"created": false,
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/_upsert",
+			Method: http.MethodPost,
+			Body: CR{
+				"id":   10,
+				"body": "upserted",
+			},
+			Result: CR{
+				"response": CR{
+					"id":      10,
+					"created": false,
+				},
+			},
+		},
+		Case{
+			Path: "/notes/10",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":      10,
+						"item_id": nil,
+						"body":    "upserted",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/notes/",
+			Method: http.MethodDelete,
+			Query:  "",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: empty filter, pass all=true to affect every record",
+			},
+		},
+		Case{
+			Path:   "/notes/?id__gte=2",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"matched": 3,
+					"deleted": 3,
+				},
+			},
+		},
+		Case{
+			Path:  "/order",
+			Query: "unit%20price__gte=20&sort=-due-date&fields=id,unit%20price",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{
+							"id":         2,
+							"unit price": 25,
+						},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/order/",
+			Method: http.MethodPut,
+			Body: CR{
+				"unit price": 40,
+				"due-date":   "friday",
+			},
+			Result: CR{
+				"response": CR{
+					"id": 3,
+				},
+			},
+		},
+		Case{
+			Path:   "/order/3",
+			Method: http.MethodPost,
+			Body: CR{
+				"due-date": "sunday",
+			},
+			Result: CR{
+				"response": CR{
+					"updated": 1,
+				},
+			},
+		},
+		Case{
+			Path: "/order/3",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"id":         3,
+						"unit price": 40,
+						"due-date":   "sunday",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/order/3",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"deleted": 1,
+				},
+			},
+		},
+		Case{
+			Path:   "/order/?due-date__isnull=true",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"matched": 1,
+					"deleted": 1,
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"name": "server default key",
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: key field code must be passed, it is not known after insert",
+			},
+		},
+		Case{
+			Path:  "/codes",
+			Query: "count=exact&fields=code",
+			Result: CR{
+				"response": CR{
+					"records": []CR{},
+					"total":   0,
+					"limit":   5,
+					"offset":  0,
+				},
+			},
+		},
+		// sqlite has no RETURNING path, records are inserted one by one and keys are read back
+		Case{
+			Path:   "/codes/_bulk",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{"code": "k1", "name": "first"},
+				CR{"name": "server default key"},
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid query: key field code must be passed, it is not known after insert",
+			},
+		},
+		Case{
+			Path:   "/codes/_bulk",
+			Method: http.MethodPost,
+			Body: []CR{
+				CR{"code": "k1", "name": "first"},
+				CR{"code": "k2", "name": "second"},
+			},
+			Result: CR{
+				"response": CR{
+					"ids":    []string{"k1", "k2"},
+					"errors": []CR{},
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/?code__in=k1,k2",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"matched": 2,
+					"deleted": 2,
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"code": "a,b",
+				"name": "comma in key",
+			},
+			Result: CR{
+				"response": CR{
+					"id": "a,b",
+				},
+			},
+		},
+		Case{
+			Path: "/codes/a,b",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"code": "a,b",
+						"name": "comma in key",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "update",
+						"table": "codes",
+						"id":    "a,b",
+						"data":  CR{"name": "renamed in batch"},
+					},
+					CR{
+						"op":    "update",
+						"table": "codes",
+						"id":    "a,b",
+						"data":  CR{"name": "comma in key"},
+					},
+				},
+			},
+			Result: CR{
+				"response": CR{
+					"results": []CR{
+						CR{"op": "update", "table": "codes", "id": "a,b", "updated": 1},
+						CR{"op": "update", "table": "codes", "id": "a,b", "updated": 1},
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/c",
+			Method: http.MethodPut,
+			Body: CR{
+				"name": "comma in key",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:  "/codes",
+			Query: "count=exact&fields=code",
+			Result: CR{
+				"response": CR{
+					"records": []CR{
+						CR{"code": "a,b"},
+					},
+					"total":  1,
+					"limit":  5,
+					"offset": 0,
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/",
+			Method: http.MethodPut,
+			Body: CR{
+				"code": "c",
+				"name": "other",
+			},
+			Result: CR{
+				"response": CR{
+					"id": "c",
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/c",
+			Method: http.MethodPost,
+			Body: CR{
+				"name": "comma in key",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "create",
+						"table": "codes",
+						"data":  CR{"code": "d", "name": "other"},
+					},
+				},
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "operation 0: record with the same unique key exists",
+			},
+		},
+		Case{
+			Path:   "/codes/_upsert?key=codes_name",
+			Method: http.MethodPost,
+			Body: CR{
+				"code": "q",
+				"name": "other",
+			},
+			Result: CR{
+				"response": CR{
+					"id":      "c",
+					"created": false,
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/_upsert",
+			Method: http.MethodPost,
+			Body: CR{
+				"code": "c",
+				"name": "comma in key",
+			},
+			Status: http.StatusConflict,
+			Result: CR{
+				"error": "record with the same unique key exists",
+			},
+		},
+		Case{
+			Path: "/codes/c",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"code": "c",
+						"name": "other",
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/codes/c",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"deleted": 1,
+				},
+			},
+		},
+		Case{
+			Path:  "/items_users/_",
+			Query: "pk.item_id=1&pk.user_id=2",
+			Result: CR{
+				"response": CR{
+					"record": CR{
+						"item_id": 1,
+						"user_id": 2,
+					},
+				},
+			},
+		},
+		Case{
+			Path:   "/items_users/_",
+			Query:  "pk.item_id=1",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid param: id: expect one pk.user_id param",
+			},
+		},
+		Case{
+			Path:   "/items_users/_",
+			Query:  "pk.item_id=1&pk.user_id=2&pk.title=x",
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "invalid param: id: title is not key field",
+			},
+		},
+		Case{
+			Path:   "/codes/_?pk.code=a,b",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"deleted": 1,
+				},
+			},
+		},
+		Case{
+			Path:   "/items/1",
+			Method: http.MethodPost,
+			Body:   CR{},
+			Result: CR{
+				"response": CR{
+					"updated": 0,
+				},
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []interface{}{nil},
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "operation 0: invalid query: empty operation",
+			},
+		},
+		Case{
+			Path:   "/_batch",
+			Method: http.MethodPost,
+			Body: CR{
+				"operations": []CR{
+					CR{
+						"op":    "create",
+						"table": "notes",
+						"ref":   "0",
+						"data":  CR{"body": "shadows index"},
+					},
+				},
+			},
+			Status: http.StatusBadRequest,
+			Result: CR{
+				"error": "operation 0: invalid query: ref 0 is a number, numbers refer to operations by index",
+			},
+		},
+		Case{
+			Path:   "/items/3",
+			Method: http.MethodDelete,
+			Result: CR{
+				"response": CR{
+					"deleted": 1,
+				},
+			},
+		},
+	}
+
+	runCases(t, ts, db, cases)
+}
+
+// TestSQLiteTimeout читает view, который считает до ста миллионов: дедлайн срабатывает
+// посреди чтения строк, и это должно быть 504, а не обрезанная страница
+func TestSQLiteTimeout(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "explorer.db") + "?_foreign_keys=on&_busy_timeout=5000"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	_, err = db.Exec(`CREATE VIEW slow AS
+  WITH RECURSIVE n(x) AS (SELECT 1 UNION ALL SELECT x + 1 FROM n LIMIT 100000000)
+  SELECT CAST(x AS INTEGER) AS x FROM n`)
+	if err != nil {
+		panic(err)
+	}
+
+	explorer := dbexplorer.NewSqlExplorer(db,
+		dbexplorer.WithDialect(dbexplorer.SQLite()),
+		dbexplorer.WithTimeouts(100*time.Millisecond, 100*time.Millisecond),
+	)
+	expHandler := api.NewExplorerHandler(explorer)
+	handler := router.NewMuxRouter()
+	expHandler.RegisterRoutes(handler)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	runCases(t, ts, db, []Case{
+		Case{
+			Path:   "/slow",
+			Query:  "x=-1",
+			Status: http.StatusGatewayTimeout,
+			Result: CR{
+				"error": "query timeout",
+			},
+		},
+	})
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	_, err = explorer.GetRecords(ctx, "slow", &dbexplorer.RecordsQuery{
+		Filters: []*dbexplorer.Filter{dbexplorer.ParseFilter("x", "-1")},
+		Limit:   5,
+	})
+	if !errors.Is(err, context.Canceled) {
+		t.Errorf("cancelled read: expected context.Canceled, got %v", err)
+	}
+}
+
+// TestSQLiteLinks проверяет заголовок Link курсорной пагинации: на последней странице его нет совсем
+func TestSQLiteLinks(t *testing.T) {
+	dsn := "file:" + filepath.Join(t.TempDir(), "explorer.db") + "?_foreign_keys=on&_busy_timeout=5000"
+	db, err := sql.Open("sqlite3", dsn)
+	if err != nil {
+		panic(err)
+	}
+	defer db.Close()
+
+	PrepareSQLiteApis(db)
+
+	explorer := dbexplorer.NewSqlExplorer(db, dbexplorer.WithDialect(dbexplorer.SQLite()))
+	expHandler := api.NewExplorerHandler(explorer)
+	handler := router.NewMuxRouter()
+	expHandler.RegisterRoutes(handler)
+
+	ts := httptest.NewServer(handler)
+	defer ts.Close()
+
+	cases := []struct {
+		query string
+		links []string
+	}{
+		{"limit=1&cursor=", []string{`</items/?cursor=eyJmIjpbImlkIl0sInYiOlsxXX0&limit=1>; rel="next"`}},
+		{"limit=5&cursor=", nil},
+	}
+
+	for _, c := range cases {
+		resp, err := client.Get(ts.URL + "/items/?" + c.query)
+		if err != nil {
+			t.Fatalf("%s: request error: %v", c.query, err)
+		}
+		resp.Body.Close()
+
+		if links := resp.Header.Values("Link"); !reflect.DeepEqual(links, c.links) {
+			t.Errorf("%s: expected Link %q, got %q", c.query, c.links, links)
+		}
+	}
+}